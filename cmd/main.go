@@ -5,12 +5,21 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/sandroJayas/storage-service/config"
 	"github.com/sandroJayas/storage-service/controllers"
+	"github.com/sandroJayas/storage-service/domain/blob"
+	"github.com/sandroJayas/storage-service/domain/event"
+	"github.com/sandroJayas/storage-service/domain/ratelimit"
+	"github.com/sandroJayas/storage-service/infrastructure/blobstore"
+	"github.com/sandroJayas/storage-service/infrastructure/jwks"
+	"github.com/sandroJayas/storage-service/infrastructure/publisher"
+	ratelimiter "github.com/sandroJayas/storage-service/infrastructure/ratelimit"
 	"github.com/sandroJayas/storage-service/infrastructure/repository"
+	"github.com/sandroJayas/storage-service/middleware"
 	"github.com/sandroJayas/storage-service/routes"
 	"github.com/sandroJayas/storage-service/usecase"
 	"github.com/sandroJayas/storage-service/utils"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"net/http"
 	"os"
 	"os/signal"
@@ -27,19 +36,47 @@ func main() {
 	config.LoadEnv()
 	db := config.ConnectDB()
 
+	blobs, blobController := newBlobStore()
+
 	boxRepo := repository.NewGormBoxRepository(db)
-	boxService := usecase.NewBoxService(boxRepo)
+	boxService := usecase.NewBoxService(boxRepo, blobs, config.AppConfig.BlobURLTTL)
 	boxController := controllers.NewBoxController(boxService)
 
 	itemRepo := repository.NewGormItemRepository(db)
-	itemService := usecase.NewItemService(itemRepo, boxRepo)
+	itemImageRepo := repository.NewGormItemImageRepository(db)
+	itemMoveRepo := repository.NewGormItemMoveRepository(db)
+	itemService := usecase.NewItemService(itemRepo, boxRepo, itemImageRepo, itemMoveRepo, blobs, config.AppConfig.BlobURLTTL)
 	itemController := controllers.NewItemController(itemService, boxService)
 
+	orderRepo := repository.NewGormOrderRepository(db)
+	orderService := usecase.NewOrderService(orderRepo, boxRepo)
+	orderController := controllers.NewOrderController(orderService)
+
+	locationRepo := repository.NewGormLocationRepository(db)
+	locationService := usecase.NewLocationService(locationRepo, boxRepo)
+	locationController := controllers.NewLocationController(locationService)
+
+	idempotencyRepo := repository.NewGormIdempotencyRepository(db)
+
+	revocationRepo := repository.NewGormRevocationRepository(db)
+	revocationService := usecase.NewTokenRevocationService(revocationRepo, config.AppConfig.RevocationCacheTTL)
+	revocationController := controllers.NewRevocationController(revocationService)
+
+	apiKeyService := usecase.NewServiceAPIKeyService(repository.NewGormServiceAPIKeyRepository(db))
+	apiKeyController := controllers.NewServiceAPIKeyController(apiKeyService)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := startOutboxRelay(relayCtx, db)
+	go purgeIdempotencyKeys(relayCtx, idempotencyRepo)
+	go purgeRevokedTokens(relayCtx, revocationRepo)
+	verifier := newTokenVerifier(relayCtx)
+	rateLimiter := newRateLimiter()
+
 	shutdown := utils.InitTracer()
 	defer shutdown(context.Background())
 
 	r := gin.Default()
-	routes.RegisterStorageRoutes(r, boxController, itemController, db)
+	routes.RegisterStorageRoutes(r, boxController, itemController, orderController, locationController, blobController, revocationController, apiKeyController, boxRepo, idempotencyRepo, verifier, revocationService, apiKeyService, rateLimiter, db)
 	r.Use(otelgin.Middleware("storage-service"))
 
 	//graceful shutdown
@@ -68,4 +105,150 @@ func main() {
 	} else {
 		utils.Logger.Info("✅ Server shutdown completed")
 	}
+
+	stopRelay()
+	<-relayDone
+}
+
+// newBlobStore builds the blob store selected by config. The controller serving
+// signed downloads is only returned for the local store; S3 serves its own.
+func newBlobStore() (blob.BlobStore, *controllers.BlobController) {
+	cfg := config.AppConfig
+	switch cfg.BlobStore {
+	case "s3":
+		store, err := blobstore.NewS3BlobStore(context.Background(), cfg.S3Endpoint, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3Bucket, cfg.S3UseSSL)
+		if err != nil {
+			utils.Logger.Fatal("failed to init S3 blob store", zap.Error(err))
+		}
+		return store, nil
+	case "local":
+		signingKey := cfg.BlobSigningKey
+		if signingKey == "" {
+			signingKey = cfg.JWTSecret
+		}
+		if signingKey == "" {
+			utils.Logger.Fatal("BLOB_SIGNING_KEY or JWT_SECRET must be set for the local blob store")
+		}
+		store, err := blobstore.NewLocalBlobStore(cfg.BlobLocalDir, cfg.BlobPublicBaseURL, signingKey)
+		if err != nil {
+			utils.Logger.Fatal("failed to init local blob store", zap.Error(err))
+		}
+		return store, controllers.NewBlobController(store)
+	default:
+		utils.Logger.Fatal("unknown blob store", zap.String("blob_store", cfg.BlobStore))
+		return nil, nil
+	}
+}
+
+// newTokenVerifier builds the access token verifier from config. With JWKS_URL
+// set the key set is loaded now and reloaded in the background until ctx is done.
+func newTokenVerifier(ctx context.Context) *middleware.TokenVerifier {
+	cfg := config.AppConfig
+	verifierCfg := middleware.TokenVerifierConfig{
+		Issuer:   cfg.JWTIssuer,
+		Audience: cfg.JWTAudience,
+		Leeway:   cfg.JWTLeeway,
+	}
+	if cfg.JWKSURL != "" {
+		if cfg.JWKSRefreshInterval <= 0 {
+			utils.Logger.Fatal("JWKS_REFRESH_INTERVAL must be positive", zap.Duration("interval", cfg.JWKSRefreshInterval))
+		}
+		keys, err := jwks.NewKeySet(ctx, cfg.JWKSURL)
+		if err != nil {
+			utils.Logger.Fatal("failed to load JWKS", zap.String("source", cfg.JWKSURL), zap.Error(err))
+		}
+		go keys.Run(ctx, cfg.JWKSRefreshInterval)
+		verifierCfg.Keys = keys
+	}
+	if cfg.JWTAllowHS256 {
+		verifierCfg.HMACSecret = cfg.JWTSecret
+	}
+
+	verifier, err := middleware.NewTokenVerifier(verifierCfg)
+	if err != nil {
+		utils.Logger.Fatal("failed to init token verifier", zap.Error(err))
+	}
+	return verifier
+}
+
+// newRateLimiter builds the rate limiter from config, counting in the Redis
+// server at RATE_LIMIT_REDIS_ADDR if set and in memory otherwise.
+func newRateLimiter() *middleware.RateLimiter {
+	cfg := config.AppConfig
+	rules, err := ratelimit.ParseRules(cfg.RateLimits)
+	if err != nil {
+		utils.Logger.Fatal("invalid RATE_LIMITS", zap.Error(err))
+	}
+
+	var limiter ratelimit.Limiter
+	if cfg.RateLimitRedisAddr != "" {
+		limiter = ratelimiter.NewRedisLimiter(cfg.RateLimitRedisAddr, cfg.RateLimitRedisPassword, cfg.RateLimitRedisDB)
+	} else {
+		utils.Logger.Warn("RATE_LIMIT_REDIS_ADDR is not set, rate limits are counted per instance")
+		limiter = ratelimiter.NewMemoryLimiter()
+	}
+	return middleware.NewRateLimiter(limiter, rules)
+}
+
+// startOutboxRelay runs the outbox relay in the background with the publisher
+// selected by config. The returned channel is closed once the relay has stopped.
+func startOutboxRelay(ctx context.Context, db *gorm.DB) <-chan struct{} {
+	done := make(chan struct{})
+
+	var pub event.Publisher
+	switch {
+	case config.AppConfig.OutboxWebhookURL != "":
+		pub = publisher.NewWebhookPublisher(config.AppConfig.OutboxWebhookURL, config.AppConfig.OutboxWebhookSecret)
+	case config.AppConfig.OutboxFilePath != "":
+		pub = publisher.NewFilePublisher(config.AppConfig.OutboxFilePath)
+	default:
+		utils.Logger.Warn("No outbox publisher configured, events will stay pending")
+		close(done)
+		return done
+	}
+
+	relay := usecase.NewOutboxRelay(repository.NewGormOutboxRepository(db), pub, config.AppConfig.OutboxPollInterval)
+	go func() {
+		defer close(done)
+		relay.Run(ctx)
+	}()
+	return done
+}
+
+// purgeIdempotencyKeys deletes expired idempotency records every hour until ctx is done.
+func purgeIdempotencyKeys(ctx context.Context, repo *repository.GormIdempotencyRepository) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := repo.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				utils.Logger.Warn("failed to purge idempotency keys", zap.Error(err))
+				continue
+			}
+			utils.Logger.Debug("purged idempotency keys", zap.Int64("deleted", deleted))
+		}
+	}
+}
+
+// purgeRevokedTokens deletes revocations of expired tokens every hour until ctx is done.
+func purgeRevokedTokens(ctx context.Context, repo *repository.GormRevocationRepository) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := repo.DeleteExpired(ctx, time.Now())
+			if err != nil {
+				utils.Logger.Warn("failed to purge revoked tokens", zap.Error(err))
+				continue
+			}
+			utils.Logger.Debug("purged revoked tokens", zap.Int64("deleted", deleted))
+		}
+	}
 }
@@ -6,15 +6,60 @@ import (
 	"github.com/sandroJayas/storage-service/utils"
 	"go.uber.org/zap"
 	"log"
+	"time"
 )
 
 type EnvConfig struct {
 	DatabaseURL          string `env:"DATABASE_URL,required"`
-	JWTSecret            string `env:"JWT_SECRET,required"`
+	JWTSecret            string `env:"JWT_SECRET"`
 	AppEnv               string `env:"APP_ENV" envDefault:"testing"`
 	HoneycombServiceName string `env:"HONEYCOMB_SERVICE_NAME,required"`
 	HoneycombEndpoint    string `env:"OTEL_EXPORTER_OTLP_ENDPOINT,required"`
 	HoneycombHeaders     string `env:"OTEL_EXPORTER_OTLP_HEADERS,required"`
+
+	// Outbox relay: events go to the webhook if set, otherwise to the file, otherwise stay pending
+	OutboxWebhookURL    string        `env:"OUTBOX_WEBHOOK_URL"`
+	OutboxWebhookSecret string        `env:"OUTBOX_WEBHOOK_SECRET"`
+	OutboxFilePath      string        `env:"OUTBOX_FILE_PATH"`
+	OutboxPollInterval  time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"2s"`
+
+	// Blob storage for item images: "local" keeps files on disk, "s3" uses an S3-compatible bucket
+	BlobStore         string        `env:"BLOB_STORE" envDefault:"local"`
+	BlobLocalDir      string        `env:"BLOB_LOCAL_DIR" envDefault:"./data/blobs"`
+	BlobPublicBaseURL string        `env:"BLOB_PUBLIC_BASE_URL" envDefault:"http://localhost:8080"`
+	BlobSigningKey    string        `env:"BLOB_SIGNING_KEY"`
+	BlobURLTTL        time.Duration `env:"BLOB_URL_TTL" envDefault:"15m"`
+	S3Endpoint        string        `env:"S3_ENDPOINT" envDefault:"localhost:9000"`
+	S3AccessKey       string        `env:"S3_ACCESS_KEY"`
+	S3SecretKey       string        `env:"S3_SECRET_KEY"`
+	S3Bucket          string        `env:"S3_BUCKET" envDefault:"storage-service"`
+	S3UseSSL          bool          `env:"S3_USE_SSL" envDefault:"false"`
+
+	// Access tokens: RS256/ES256 tokens are verified against the JWKS at JWKS_URL (an http(s) URL or a file),
+	// reloaded every JWKS_REFRESH_INTERVAL. HS256 tokens signed with JWT_SECRET are accepted while
+	// JWT_ALLOW_HS256 is set. iss and aud are only checked when configured.
+	JWKSURL             string        `env:"JWKS_URL"`
+	JWKSRefreshInterval time.Duration `env:"JWKS_REFRESH_INTERVAL" envDefault:"5m"`
+	JWTAllowHS256       bool          `env:"JWT_ALLOW_HS256" envDefault:"true"`
+	JWTIssuer           string        `env:"JWT_ISSUER"`
+	JWTAudience         string        `env:"JWT_AUDIENCE"`
+	JWTLeeway           time.Duration `env:"JWT_LEEWAY" envDefault:"0s"`
+
+	// Revoked tokens: answers of the revocation store are cached per instance for REVOCATION_CACHE_TTL
+	RevocationCacheTTL time.Duration `env:"REVOCATION_CACHE_TTL" envDefault:"30s"`
+
+	// Rate limits as scope=requests/window rules, see ratelimit.ParseRules. Counts are kept in memory
+	// unless RATE_LIMIT_REDIS_ADDR points at a Redis-protocol server shared by all replicas.
+	RateLimits             string `env:"RATE_LIMITS" envDefault:"*=120/1m,*:warehouse_staff=600/1m,*:driver=300/1m,*:service=1200/1m,ip=1200/1m"`
+	RateLimitRedisAddr     string `env:"RATE_LIMIT_REDIS_ADDR"`
+	RateLimitRedisPassword string `env:"RATE_LIMIT_REDIS_PASSWORD"`
+	RateLimitRedisDB       int    `env:"RATE_LIMIT_REDIS_DB" envDefault:"0"`
+
+	// How long responses to POSTs with an Idempotency-Key are kept for replay
+	IdempotencyKeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
+	// Largest body of a POST with an Idempotency-Key, which is read into memory to hash it.
+	// The default leaves room for image uploads; routes with lower limits still apply their own.
+	IdempotencyMaxBodyBytes int64 `env:"IDEMPOTENCY_MAX_BODY_BYTES" envDefault:"12582912"`
 }
 
 var AppConfig *EnvConfig
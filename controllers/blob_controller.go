@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandroJayas/storage-service/domain/blob"
+	"github.com/sandroJayas/storage-service/infrastructure/blobstore"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+)
+
+type BlobController struct {
+	store *blobstore.LocalBlobStore
+}
+
+func NewBlobController(store *blobstore.LocalBlobStore) *BlobController {
+	return &BlobController{store: store}
+}
+
+// GetBlob godoc
+// @Summary Download a stored blob
+// @Description Serves a blob from the local blob store. Only reachable through the signed, expiring URLs returned by the API (e.g. an item's image_url).
+// @Tags blobs
+// @Produce octet-stream
+// @Param key path string true "Blob key"
+// @Param expires query int true "Expiry as a unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string "Invalid or expired signature"
+// @Failure 404 {object} map[string]string "Blob not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /blobs/{key} [get]
+func (bc *BlobController) GetBlob(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	f, err := bc.store.Open(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, blobstore.ErrInvalidSignature), errors.Is(err, blobstore.ErrInvalidKey):
+			utils.Logger.Warn("rejected blob download", zap.String("key", key), zap.Error(err))
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, blob.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			utils.Logger.Error("blob download failed", zap.String("key", key), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		utils.Logger.Error("blob stat failed", zap.String("key", key), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.DataFromReader(http.StatusOK, info.Size(), contentType, f, nil)
+}
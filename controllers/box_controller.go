@@ -1,14 +1,20 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
 	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/middleware"
+	"github.com/sandroJayas/storage-service/policy"
 	"github.com/sandroJayas/storage-service/usecase"
 	"github.com/sandroJayas/storage-service/utils"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type BoxController struct {
@@ -25,9 +31,12 @@ func NewBoxController(service *usecase.BoxService) *BoxController {
 // @Tags boxes
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Unique key per logical request; retries with the same key replay the first response"
 // @Param body body dto.CreateBoxRequest true "Box data"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string "A request with the same Idempotency-Key is still in progress"
+// @Failure 422 {object} map[string]string "Idempotency-Key reused with a different payload"
 // @Failure 500 {object} map[string]string
 // @Router /boxes [post]
 func (bc *BoxController) CreateBox(c *gin.Context) {
@@ -56,15 +65,34 @@ func (bc *BoxController) CreateBox(c *gin.Context) {
 
 // ListUserBoxes godoc
 // @Summary List user boxes
-// @Description Get all boxes owned by the user
+// @Description Get one page of the boxes owned by the user. Items are only included with include=items.
 // @Tags boxes
 // @Produce json
-// @Success 200 {object} map[string][]dto.BoxResponse
+// @Param status query string false "Filter by status"
+// @Param packing_mode query string false "Filter by packing mode (self or sort)"
+// @Param location_id query string false "Filter by storage location ID"
+// @Param sort query string false "created_at (oldest first) or -created_at (newest first, default)"
+// @Param limit query int false "Page size, 1-100 (default 50)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include query string false "Set to items to include each box's items"
+// @Success 200 {object} dto.BoxListResponse
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /boxes [get]
 func (bc *BoxController) ListUserBoxes(c *gin.Context) {
+	var query dto.ListBoxesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.Logger.Warn("Invalid box list query", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	userID := c.MustGet("user_id").(uuid.UUID)
-	boxes, err := bc.service.ListUserBoxes(c.Request.Context(), userID)
+	page, err := bc.service.ListUserBoxes(c.Request.Context(), userID, query)
+	if errors.Is(err, usecase.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		utils.Logger.Error("Failed to list user boxes",
 			zap.String("user_id", userID.String()),
@@ -74,17 +102,112 @@ func (bc *BoxController) ListUserBoxes(c *gin.Context) {
 	}
 	utils.Logger.Info("User boxes retrieved successfully",
 		zap.String("user_id", userID.String()),
-		zap.Int("count", len(boxes)))
-	c.JSON(http.StatusOK, gin.H{"boxes": boxes})
+		zap.Int("count", len(page.Boxes)),
+		zap.Int64("total", page.Total))
+	c.JSON(http.StatusOK, page)
+}
+
+var exportContentTypes = map[string]string{
+	"csv":  "text/csv; charset=utf-8",
+	"json": "application/json",
+	"pdf":  "application/pdf",
+}
+
+// ExportInventory godoc
+// @Summary Export the user's inventory
+// @Description Download every box owned by the user with its status, location and items. The file is streamed as it is generated; pdf gives a printable manifest grouped by box.
+// @Tags boxes
+// @Produce text/csv
+// @Produce json
+// @Produce application/pdf
+// @Param format query string false "csv (default), json or pdf"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /boxes/export [get]
+func (bc *BoxController) ExportInventory(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": usecase.ErrUnsupportedExportFormat.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	filename := "inventory-" + time.Now().UTC().Format("20060102") + "." + format
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	// Once bytes have gone out the status can no longer change, so a failure
+	// midway leaves a truncated file and is only logged
+	if err := bc.service.ExportInventory(c.Request.Context(), userID, format, c.Writer); err != nil {
+		utils.Logger.Error("Inventory export failed",
+			zap.String("user_id", userID.String()),
+			zap.String("format", format),
+			zap.Error(err))
+		c.Abort()
+		return
+	}
+	utils.Logger.Info("Inventory exported",
+		zap.String("user_id", userID.String()),
+		zap.String("format", format))
+}
+
+// ListAllBoxes godoc
+// @Summary List boxes across all users
+// @Description Listing of every box with filters, paginated like GET /boxes. Requires box:read_any.
+// @Tags admin
+// @Produce json
+// @Param user_id query string false "Filter by owner"
+// @Param status query string false "Filter by status"
+// @Param packing_mode query string false "Filter by packing mode (self or sort)"
+// @Param location_id query string false "Filter by storage location ID"
+// @Param created_from query string false "Created at or after (RFC 3339)"
+// @Param created_to query string false "Created before (RFC 3339)"
+// @Param sort query string false "created_at (oldest first) or -created_at (newest first, default)"
+// @Param limit query int false "Page size, 1-100 (default 50)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param include query string false "Set to items to include each box's items"
+// @Success 200 {object} dto.BoxListResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Permission box:read_any required"
+// @Failure 500 {object} map[string]string
+// @Router /admin/boxes [get]
+func (bc *BoxController) ListAllBoxes(c *gin.Context) {
+	var query dto.AdminListBoxesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.Logger.Warn("Invalid admin box list query", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := bc.service.ListAllBoxes(c.Request.Context(), query)
+	if errors.Is(err, usecase.ErrInvalidCursor) || errors.Is(err, usecase.ErrInvalidDateRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		utils.Logger.Error("Failed to list boxes for employee", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	employeeID := middleware.CurrentPrincipal(c).UserID
+	utils.Logger.Info("Employee listed boxes",
+		zap.String("user_id", employeeID.String()),
+		zap.Int("count", len(page.Boxes)),
+		zap.Int64("total", page.Total))
+	c.JSON(http.StatusOK, page)
 }
 
 // GetBoxByID godoc
 // @Summary Get a box by ID
-// @Description Get box and items by ID
+// @Description Get box and items by ID. The ETag header carries the box version for use in If-Match.
 // @Tags boxes
 // @Produce json
-// @Param id path string true "Box ID"
+// @Param id path string true "Box ID or code"
 // @Success 200 {object} map[string]dto.BoxResponse
+// @Header 200 {string} ETag "Box version"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /boxes/{id} [get]
@@ -110,21 +233,123 @@ func (bc *BoxController) GetBoxByID(c *gin.Context) {
 	utils.Logger.Info("Box retrieved successfully",
 		zap.String("box_id", boxID.String()),
 		zap.String("user_id", userID.String()))
+	setETag(c, box.Version)
 	c.JSON(http.StatusOK, gin.H{"box": box})
 }
 
+var labelContentTypes = map[string]string{
+	"png": "image/png",
+	"pdf": "application/pdf",
+}
+
+// GetBoxLabel godoc
+// @Summary Get a printable box label
+// @Description Returns a label with a QR code encoding the box ID and a short code for people to read.
+// @Tags boxes
+// @Produce image/png
+// @Produce application/pdf
+// @Param id path string true "Box ID or code"
+// @Param format query string false "png (default) or pdf"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Invalid box ID or format"
+// @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 500 {object} map[string]string
+// @Router /boxes/{id}/label [get]
+func (bc *BoxController) GetBoxLabel(c *gin.Context) {
+	boxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid box ID for label",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid box ID"})
+		return
+	}
+	format := c.DefaultQuery("format", "png")
+	contentType, ok := labelContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": usecase.ErrUnsupportedLabelFormat.Error()})
+		return
+	}
+
+	if err := assertBoxAccess(bc.service, c, boxID, policy.BoxReadAny); err != nil {
+		return
+	}
+
+	label, err := bc.service.BoxLabel(c.Request.Context(), boxID, format)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "box not found"})
+		return
+	}
+	if err != nil {
+		utils.Logger.Error("Failed to render box label",
+			zap.String("box_id", boxID.String()),
+			zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="box-`+boxID.String()+`.`+format+`"`)
+	c.Data(http.StatusOK, contentType, label)
+}
+
+// PrintBoxLabels godoc
+// @Summary Print labels for many boxes
+// @Description Requires box:print_labels. Returns one PDF with a label per box, in the requested order.
+// @Tags admin
+// @Accept json
+// @Produce application/pdf
+// @Param body body dto.BoxLabelBatchRequest true "Boxes to print, at most 100"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Permission box:print_labels required"
+// @Failure 404 {object} map[string]interface{} "Some boxes do not exist; body lists them"
+// @Failure 500 {object} map[string]string
+// @Router /admin/boxes/labels [post]
+func (bc *BoxController) PrintBoxLabels(c *gin.Context) {
+	var req dto.BoxLabelBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid label batch request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	labels, err := bc.service.BoxLabels(c.Request.Context(), req.BoxIDs)
+	if err != nil {
+		var missingErr *usecase.MissingBoxesError
+		switch {
+		case errors.As(err, &missingErr):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error(), "missing": missingErr.IDs})
+		case errors.Is(err, usecase.ErrEmptyLabelBatch), errors.Is(err, usecase.ErrLabelBatchTooLarge):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			utils.Logger.Error("Failed to render box labels", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	employeeID := middleware.CurrentPrincipal(c).UserID
+	utils.Logger.Info("Employee printed box labels",
+		zap.String("user_id", employeeID.String()),
+		zap.Int("count", len(req.BoxIDs)))
+	c.Header("Content-Disposition", `attachment; filename="box-labels.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", labels)
+}
+
 // UpdateStatus godoc
 // @Summary Update box status
-// @Description Update the status of a box. Only employees can set status to 'stored' or 'returned'.
+// @Description Update the status of a box. Only transitions in the box lifecycle are accepted, and each target status needs its box:set_status permission.
 // @Tags boxes
 // @Accept json
 // @Produce json
-// @Param id path string true "Box ID"
-// @Param body body map[string]string true "New status"
+// @Param id path string true "Box ID or code"
+// @Param If-Match header string false "ETag from GET /boxes/{id}; the update only applies to that version"
+// @Param body body map[string]string true "New status and optional note"
 // @Success 200 {object} map[string]string "Box status updated successfully"
+// @Header 200 {string} ETag "New box version"
 // @Failure 400 {object} map[string]string "Invalid box ID or status"
-// @Failure 403 {object} map[string]string "Admin access required for this status"
+// @Failure 403 {object} map[string]string "Permission box:set_status:<status> required"
 // @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 409 {object} map[string]interface{} "Transition not allowed; body lists allowed next statuses"
+// @Failure 412 {object} map[string]string "Box changed since the If-Match version"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /boxes/{id}/status [patch]
 func (bc *BoxController) UpdateStatus(c *gin.Context) {
@@ -139,6 +364,7 @@ func (bc *BoxController) UpdateStatus(c *gin.Context) {
 
 	var body struct {
 		Status string `json:"status" binding:"required,oneof=in_transit pending_pack pending_pickup stored returned disposed"`
+		Note   string `json:"note"`
 	}
 	if err := c.ShouldBindJSON(&body); err != nil {
 		utils.Logger.Warn("Invalid status update payload",
@@ -148,37 +374,150 @@ func (bc *BoxController) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	accountType := c.GetString("account_type")
-	if (body.Status == "stored" || body.Status == "returned") && accountType != "employee" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	userID := c.MustGet("user_id").(uuid.UUID)
-	if err := assertBoxOwnership(bc.service, c, boxID, userID, &accountType); err != nil {
+	if err := assertBoxAccess(bc.service, c, boxID, policy.BoxReadAny); err != nil {
 		return
 	}
 
-	if err := bc.service.UpdateStatus(c.Request.Context(), boxID, body.Status); err != nil {
-		utils.Logger.Error("Failed to update box status", zap.String("box_id", boxID.String()), zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	version, err := bc.service.UpdateStatus(c.Request.Context(), boxID, middleware.CurrentPrincipal(c), body.Status, body.Note, ifMatch)
+	if err != nil {
+		var transitionErr *usecase.StatusTransitionError
+		switch {
+		case errors.Is(err, repository.ErrVersionConflict):
+			c.JSON(versionConflictStatus(c), gin.H{"error": err.Error()})
+		case errors.As(err, &transitionErr):
+			utils.Logger.Warn("Box status transition rejected",
+				zap.String("box_id", boxID.String()),
+				zap.String("from", transitionErr.From),
+				zap.String("to", transitionErr.To))
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "allowed": transitionErr.Allowed})
+		case errors.Is(err, policy.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrStatusChanged):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			utils.Logger.Error("Failed to update box status", zap.String("box_id", boxID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 	utils.Logger.Info("Box status updated successfully",
 		zap.String("box_id", boxID.String()),
 		zap.String("new_status", body.Status))
+	setETag(c, version)
 	c.JSON(http.StatusOK, gin.H{"message": "Box status updated"})
 }
 
+// ScanBox godoc
+// @Summary Check a box in or out by scanning it
+// @Description Requires box:scan. Applies the next lifecycle step for the scanned box: in_transit boxes are checked in at the scanning location (stored, or pending_pack for sort-packed boxes), pending_pack boxes are stored there, stored boxes move to pending_pickup when scanned at their own location, and pending_pickup boxes are returned and leave their location. The history entry records who scanned and where.
+// @Tags boxes
+// @Accept json
+// @Produce json
+// @Param body body dto.ScanBoxRequest true "Scanned box code or ID and the scanning location"
+// @Success 200 {object} dto.ScanBoxResponse
+// @Header 200 {string} ETag "New box version"
+// @Failure 400 {object} map[string]string "Invalid payload or box code"
+// @Failure 403 {object} map[string]string "Permission box:scan, or the permission for the resulting status, required"
+// @Failure 404 {object} map[string]string "Box or location not found"
+// @Failure 409 {object} map[string]string "Box cannot be scanned in its status, changed meanwhile, is stored at another location, or the location is full"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/scan [post]
+func (bc *BoxController) ScanBox(c *gin.Context) {
+	var req dto.ScanBoxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid scan payload", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	employee := middleware.CurrentPrincipal(c)
+	result, err := bc.service.ScanBox(c.Request.Context(), employee, req)
+	if err != nil {
+		var notApplicable *usecase.ScanNotApplicableError
+		var transitionErr *usecase.StatusTransitionError
+		switch {
+		case errors.Is(err, usecase.ErrInvalidBoxCode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "box or location not found"})
+		case errors.Is(err, policy.ErrForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case errors.As(err, &notApplicable), errors.As(err, &transitionErr),
+			errors.Is(err, usecase.ErrStatusChanged), errors.Is(err, usecase.ErrScanWrongLocation),
+			errors.Is(err, repository.ErrLocationFull):
+			utils.Logger.Warn("Box scan rejected",
+				zap.String("box_code", req.BoxCode),
+				zap.String("location_id", req.LocationID.String()),
+				zap.Error(err))
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			utils.Logger.Error("Box scan failed", zap.String("box_code", req.BoxCode), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	utils.Logger.Info("Box scanned",
+		zap.String("box_id", result.BoxID.String()),
+		zap.String("user_id", employee.UserID.String()),
+		zap.String("location_id", req.LocationID.String()),
+		zap.String("from", result.FromStatus),
+		zap.String("to", result.ToStatus))
+	setETag(c, result.Version)
+	c.JSON(http.StatusOK, result)
+}
+
+// GetStatusHistory godoc
+// @Summary Get box status history
+// @Description Returns every status change of the box, oldest first, with who made it
+// @Tags boxes
+// @Produce json
+// @Param id path string true "Box ID or code"
+// @Success 200 {object} map[string][]dto.BoxStatusEventResponse
+// @Failure 400 {object} map[string]string "Invalid box ID"
+// @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/{id}/history [get]
+func (bc *BoxController) GetStatusHistory(c *gin.Context) {
+	boxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid box ID for status history",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid box ID"})
+		return
+	}
+
+	if err := assertBoxAccess(bc.service, c, boxID, policy.BoxReadAny); err != nil {
+		return
+	}
+
+	history, err := bc.service.GetStatusHistory(c.Request.Context(), boxID)
+	if err != nil {
+		utils.Logger.Error("Failed to get box status history",
+			zap.String("box_id", boxID.String()),
+			zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
 // DeleteBox godoc
 // @Summary Delete a box (soft delete)
-// @Description Soft delete a box. Employees can delete any box, users can only delete their own.
+// @Description Soft delete a box. Users can delete their own boxes, and any box with box:write_any.
 // @Tags boxes
 // @Produce json
-// @Param id path string true "Box ID"
+// @Param id path string true "Box ID or code"
+// @Param If-Match header string false "ETag from GET /boxes/{id}; the box is only deleted at that version"
 // @Success 200 {object} map[string]string "Box deleted successfully"
 // @Failure 400 {object} map[string]string "Invalid box ID"
 // @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 412 {object} map[string]string "Box changed since the If-Match version"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /boxes/{id} [delete]
 func (bc *BoxController) DeleteBox(c *gin.Context) {
@@ -191,13 +530,20 @@ func (bc *BoxController) DeleteBox(c *gin.Context) {
 		return
 	}
 
-	userID := c.MustGet("user_id").(uuid.UUID)
-	accountType := c.GetString("account_type")
-	if err := assertBoxOwnership(bc.service, c, boxID, userID, &accountType); err != nil {
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 
-	if err := bc.service.DeleteBox(c.Request.Context(), boxID); err != nil {
+	if err := assertBoxAccess(bc.service, c, boxID, policy.BoxWriteAny); err != nil {
+		return
+	}
+
+	if err := bc.service.DeleteBox(c.Request.Context(), boxID, ifMatch); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			c.JSON(versionConflictStatus(c), gin.H{"error": err.Error()})
+			return
+		}
 		utils.Logger.Error("Failed to delete box",
 			zap.String("box_id", boxID.String()),
 			zap.Error(err))
@@ -208,15 +554,18 @@ func (bc *BoxController) DeleteBox(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Box deleted"})
 }
 
-func assertBoxOwnership(service *usecase.BoxService, c *gin.Context, boxID, userID uuid.UUID, accountType *string) error {
-	if *accountType == "employee" {
+// assertBoxAccess responds with 404 unless the caller owns the box or holds perm,
+// which grants access to other users' boxes.
+func assertBoxAccess(service *usecase.BoxService, c *gin.Context, boxID uuid.UUID, perm policy.Permission) error {
+	principal := middleware.CurrentPrincipal(c)
+	if principal.Can(perm) {
 		return nil
 	}
-	_, err := service.GetBoxByID(c.Request.Context(), boxID, userID)
+	_, err := service.GetBoxByID(c.Request.Context(), boxID, principal.UserID)
 	if err != nil {
 		utils.Logger.Warn("Unauthorized access or box not found",
 			zap.String("box_id", boxID.String()),
-			zap.String("user_id", userID.String()),
+			zap.String("user_id", principal.UserID.String()),
 			zap.Error(err))
 		c.JSON(http.StatusNotFound, gin.H{"error": "box not found or not accessible"})
 	}
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandroJayas/storage-service/domain/repository"
+)
+
+// setETag exposes a row version as the resource's ETag.
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// ifMatchVersion reads the If-Match header. The version is nil when the header
+// is absent or "*". An ETag that is not one of ours can never match, so it is
+// answered with 412 right away and ok is false.
+func ifMatchVersion(c *gin.Context) (version *int, ok bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+	v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil {
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": repository.ErrVersionConflict.Error()})
+		return nil, false
+	}
+	return &v, true
+}
+
+// versionConflictStatus answers a version conflict with 412 when the client sent
+// If-Match, and with 409 when the conflict came from a concurrent write.
+func versionConflictStatus(c *gin.Context) int {
+	if c.GetHeader("If-Match") != "" {
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusConflict
+}
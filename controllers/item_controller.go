@@ -1,16 +1,27 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
 	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/middleware"
+	"github.com/sandroJayas/storage-service/policy"
 	"github.com/sandroJayas/storage-service/usecase"
 	"github.com/sandroJayas/storage-service/utils"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// maxItemImportBytes bounds the body of a bulk item import.
+const maxItemImportBytes = 5 << 20
+
 type ItemController struct {
 	itemService *usecase.ItemService
 	boxService  *usecase.BoxService
@@ -29,11 +40,14 @@ func NewItemController(itemService *usecase.ItemService, boxService *usecase.Box
 // @Tags items
 // @Accept json
 // @Produce json
-// @Param box_id path string true "Box ID"
+// @Param box_id path string true "Box ID or code"
+// @Param Idempotency-Key header string false "Unique key per logical request; retries with the same key replay the first response"
 // @Param body body dto.AddItemRequest true "Item data"
 // @Success 201 {object} map[string]string "ID of the created item"
 // @Failure 400 {object} map[string]string "Invalid box ID or request payload"
 // @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 409 {object} map[string]string "A request with the same Idempotency-Key is still in progress"
+// @Failure 422 {object} map[string]string "Idempotency-Key reused with a different payload"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /boxes/{id}/items [post]
 func (ic *ItemController) AddItem(c *gin.Context) {
@@ -50,12 +64,15 @@ func (ic *ItemController) AddItem(c *gin.Context) {
 		return
 	}
 	userID := c.MustGet("user_id").(uuid.UUID)
-	accountType := c.GetString("account_type")
-	if err := assertBoxOwnership(ic.boxService, c, boxID, userID, &accountType); err != nil {
+	if err := assertBoxAccess(ic.boxService, c, boxID, policy.BoxWriteAny); err != nil {
 		return
 	}
-	itemID, err := ic.itemService.AddItem(c.Request.Context(), boxID, userID, req)
+	itemID, err := ic.itemService.AddItem(c.Request.Context(), boxID, middleware.CurrentPrincipal(c), req)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "box not found or not accessible"})
+			return
+		}
 		utils.Logger.Error("add item failed", zap.String("box_id", boxID.String()), zap.String("user_id", userID.String()), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -64,12 +81,102 @@ func (ic *ItemController) AddItem(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"id": itemID})
 }
 
+// ImportItems godoc
+// @Summary Bulk add items to a sort-packed box
+// @Description Adds up to 1000 items in one transaction. Send either a JSON array of items, a text/csv body, or a multipart upload with the CSV in the "file" field. CSV needs a header row naming the columns name, quantity and optionally description and image_url. If any row is invalid nothing is added and every failing row is listed.
+// @Tags items
+// @Accept json
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Box ID or code"
+// @Param body body []dto.AddItemRequest false "Items, when sending JSON"
+// @Param file formData file false "CSV file, when sending multipart"
+// @Success 201 {object} dto.ItemImportResponse
+// @Failure 400 {object} map[string]interface{} "Invalid box ID, malformed payload, box not sort-packed, or per-row errors in rows"
+// @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 413 {object} map[string]string "Too many items"
+// @Failure 415 {object} map[string]string "Unsupported content type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/{id}/items/bulk [post]
+func (ic *ItemController) ImportItems(c *gin.Context) {
+	boxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid box ID", zap.String("id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid box ID"})
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+	if err := assertBoxAccess(ic.boxService, c, boxID, policy.BoxWriteAny); err != nil {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxItemImportBytes)
+	var ids []uuid.UUID
+	switch c.ContentType() {
+	case binding.MIMEJSON:
+		var rows []dto.AddItemRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&rows); err != nil {
+			utils.Logger.Warn("invalid item import payload", zap.String("box_id", boxID.String()), zap.Error(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ids, err = ic.itemService.ImportItems(c.Request.Context(), boxID, middleware.CurrentPrincipal(c), rows)
+	case "text/csv":
+		ids, err = ic.itemService.ImportItemsCSV(c.Request.Context(), boxID, middleware.CurrentPrincipal(c), c.Request.Body)
+	case binding.MIMEMultipartPOSTForm:
+		header, formErr := c.FormFile("file")
+		if formErr != nil {
+			utils.Logger.Warn("missing item import file", zap.String("box_id", boxID.String()), zap.Error(formErr))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		file, openErr := header.Open()
+		if openErr != nil {
+			utils.Logger.Error("failed to open item import file", zap.String("box_id", boxID.String()), zap.Error(openErr))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": openErr.Error()})
+			return
+		}
+		defer file.Close()
+		ids, err = ic.itemService.ImportItemsCSV(c.Request.Context(), boxID, middleware.CurrentPrincipal(c), file)
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "send application/json, text/csv or multipart/form-data"})
+		return
+	}
+
+	if err != nil {
+		var importErr *usecase.ItemImportError
+		switch {
+		case errors.As(err, &importErr):
+			utils.Logger.Warn("item import rejected", zap.String("box_id", boxID.String()), zap.Int("invalid_rows", len(importErr.Rows)))
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "rows": importErr.Rows})
+		case errors.Is(err, usecase.ErrImportTooLarge):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		case errors.Is(err, usecase.ErrEmptyImport), errors.Is(err, usecase.ErrInvalidCSV), errors.Is(err, usecase.ErrBoxNotSortPacked):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "box not found or not accessible"})
+		default:
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+				return
+			}
+			utils.Logger.Error("item import failed", zap.String("box_id", boxID.String()), zap.String("user_id", userID.String()), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	utils.Logger.Info("items imported", zap.String("box_id", boxID.String()), zap.String("user_id", userID.String()), zap.Int("count", len(ids)))
+	c.JSON(http.StatusCreated, dto.ItemImportResponse{IDs: ids, Count: len(ids)})
+}
+
 // ListItems godoc
 // @Summary List items in a sort-packed box
-// @Description Returns all items for a given box ID. Only the box owner can access this.
+// @Description Returns all items for a given box ID. Only the box owner or callers with box:read_any can access this.
 // @Tags items
 // @Produce json
-// @Param box_id path string true "Box ID"
+// @Param box_id path string true "Box ID or code"
 // @Success 200 {object} map[string][]dto.ItemDTO "List of items"
 // @Failure 400 {object} map[string]string "Invalid box ID"
 // @Failure 404 {object} map[string]string "Box not found or inaccessible"
@@ -83,12 +190,15 @@ func (ic *ItemController) ListItems(c *gin.Context) {
 		return
 	}
 	userID := c.MustGet("user_id").(uuid.UUID)
-	accountType := c.GetString("account_type")
-	if err := assertBoxOwnership(ic.boxService, c, boxID, userID, &accountType); err != nil {
+	if err := assertBoxAccess(ic.boxService, c, boxID, policy.BoxReadAny); err != nil {
 		return
 	}
-	items, err := ic.itemService.ListBoxItems(c.Request.Context(), boxID, userID)
+	items, err := ic.itemService.ListBoxItems(c.Request.Context(), boxID, middleware.CurrentPrincipal(c))
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "box not found or not accessible"})
+			return
+		}
 		utils.Logger.Error("list items failed", zap.String("box_id", boxID.String()), zap.String("user_id", userID.String()), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -97,13 +207,44 @@ func (ic *ItemController) ListItems(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"items": items})
 }
 
+// SearchItems godoc
+// @Summary Search the user's items
+// @Description Full-text search over item names and descriptions across all of the user's boxes, ranked by relevance
+// @Tags items
+// @Produce json
+// @Param q query string true "Search text, e.g. winter jacket"
+// @Param limit query int false "Page size, 1-100 (default 20)"
+// @Param offset query int false "Number of results to skip"
+// @Success 200 {object} dto.ItemSearchResponse
+// @Failure 400 {object} map[string]string "Missing or invalid query"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/search [get]
+func (ic *ItemController) SearchItems(c *gin.Context) {
+	var query dto.ItemSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.Logger.Warn("invalid item search query", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+	results, err := ic.itemService.SearchItems(c.Request.Context(), userID, query)
+	if err != nil {
+		utils.Logger.Error("item search failed", zap.String("user_id", userID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("items searched", zap.String("user_id", userID.String()), zap.Int64("total", results.Total))
+	c.JSON(http.StatusOK, results)
+}
+
 // GetItem godoc
 // @Summary Get a single item by ID
-// @Description Returns a single item's full details
+// @Description Returns a single item's full details. The ETag header carries the item version for use in If-Match.
 // @Tags items
 // @Produce json
 // @Param id path string true "Item ID"
 // @Success 200 {object} dto.ItemDTO "Item details"
+// @Header 200 {string} ETag "Item version"
 // @Failure 400 {object} map[string]string "Invalid item ID"
 // @Failure 404 {object} map[string]string "Item not found or inaccessible"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -123,20 +264,25 @@ func (ic *ItemController) GetItem(c *gin.Context) {
 		return
 	}
 	utils.Logger.Info("item fetched", zap.String("item_id", itemID.String()), zap.String("user_id", userID.String()))
+	setETag(c, item.Version)
 	c.JSON(http.StatusOK, item)
 }
 
 // UpdateItemByID godoc
 // @Summary Update item by ID
-// @Description Updates fields of an item (name, description, quantity, image_url)
+// @Description Updates fields of an item (name, description, quantity, image_url). A changed quantity is recorded in the quantity ledger as a correction; prefer POST /items/{id}/quantity-movements to record why it changed.
 // @Tags items
 // @Accept json
 // @Produce json
 // @Param id path string true "Item ID"
+// @Param If-Match header string false "ETag from GET /items/{id}; the update only applies to that version"
 // @Param body body dto.UpdateItemRequest true "Fields to update"
 // @Success 200 {object} map[string]string "Item updated successfully"
+// @Header 200 {string} ETag "New item version"
 // @Failure 400 {object} map[string]string "Invalid item ID or payload"
 // @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 409 {object} map[string]string "Item was changed by a concurrent request"
+// @Failure 412 {object} map[string]string "Item changed since the If-Match version"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /items/{id} [patch]
 func (ic *ItemController) UpdateItemByID(c *gin.Context) {
@@ -161,7 +307,23 @@ func (ic *ItemController) UpdateItemByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := ic.itemService.UpdateItem(c.Request.Context(), itemID, userID, req); err != nil {
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	version, err := ic.itemService.UpdateItem(c.Request.Context(), itemID, userID, req, ifMatch)
+	if errors.Is(err, repository.ErrNegativeQuantity) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repository.ErrVersionConflict) {
+		utils.Logger.Warn("item update conflict",
+			zap.String("item_id", itemID.String()),
+			zap.String("user_id", userID.String()))
+		c.JSON(versionConflictStatus(c), gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		utils.Logger.Error("item update failed",
 			zap.String("item_id", itemID.String()),
 			zap.String("user_id", userID.String()),
@@ -174,18 +336,364 @@ func (ic *ItemController) UpdateItemByID(c *gin.Context) {
 		zap.String("user_id", userID.String()),
 		zap.Any("fields", req),
 	)
+	setETag(c, version)
 	c.JSON(http.StatusOK, gin.H{"message": "Item updated successfully"})
 }
 
+// AdjustQuantity godoc
+// @Summary Adjust item quantity
+// @Description Adds delta to the item's quantity and records it with a reason in the item's quantity ledger, e.g. -2 retrieved. The quantity cannot drop below zero. Only the box owner or callers with box:write_any can adjust it; the caller is recorded as actor.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param If-Match header string false "ETag from GET /items/{id}; the adjustment only applies to that version"
+// @Param Idempotency-Key header string false "Unique key per logical request; retries with the same key replay the first response"
+// @Param body body dto.QuantityAdjustmentRequest true "Quantity change and reason"
+// @Success 201 {object} dto.QuantityAdjustmentResponse
+// @Header 201 {string} ETag "New item version"
+// @Failure 400 {object} map[string]string "Invalid item ID or payload"
+// @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 409 {object} map[string]string "Quantity would drop below zero, or the item changed concurrently"
+// @Failure 412 {object} map[string]string "Item changed since the If-Match version"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/quantity-movements [post]
+func (ic *ItemController) AdjustQuantity(c *gin.Context) {
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid item ID", zap.String("id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+	var req dto.QuantityAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("invalid quantity adjustment", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	result, err := ic.itemService.AdjustQuantity(c.Request.Context(), itemID, middleware.CurrentPrincipal(c), req, ifMatch)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrDeltaSign):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "item not found or not accessible"})
+		case errors.Is(err, repository.ErrNegativeQuantity):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrVersionConflict):
+			c.JSON(versionConflictStatus(c), gin.H{"error": err.Error()})
+		default:
+			utils.Logger.Error("quantity adjustment failed",
+				zap.String("item_id", itemID.String()),
+				zap.String("user_id", userID.String()),
+				zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	utils.Logger.Info("item quantity adjusted",
+		zap.String("item_id", itemID.String()),
+		zap.String("user_id", userID.String()),
+		zap.Int("delta", req.Delta),
+		zap.String("reason", req.Reason))
+	setETag(c, result.Version)
+	c.JSON(http.StatusCreated, result)
+}
+
+// ListQuantityMovements godoc
+// @Summary Get item quantity ledger
+// @Description Lists every change of the item's quantity, oldest first, starting with the quantity it was stored with. Only the box owner or callers with box:read_any can access this.
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Success 200 {object} map[string][]dto.QuantityMovementResponse
+// @Failure 400 {object} map[string]string "Invalid item ID"
+// @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/quantity-movements [get]
+func (ic *ItemController) ListQuantityMovements(c *gin.Context) {
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid item ID", zap.String("id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	movements, err := ic.itemService.ListQuantityMovements(c.Request.Context(), itemID, middleware.CurrentPrincipal(c))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "item not found or not accessible"})
+		return
+	}
+	if err != nil {
+		utils.Logger.Error("failed to list quantity movements", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"movements": movements})
+}
+
+// UploadImage godoc
+// @Summary Upload an item image
+// @Description Deprecated: use POST /items/{id}/images. Adds a JPEG, PNG or WebP image (max 10 MB) as the item's primary image and returns the item.
+// @Tags items
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param image formData file true "Image file"
+// @Success 200 {object} dto.ItemDTO "Item with signed image URLs"
+// @Failure 400 {object} map[string]string "Invalid item ID, missing file, undecodable image or image over 40 megapixels"
+// @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 409 {object} map[string]string "Item already has the maximum number of images"
+// @Failure 413 {object} map[string]string "Image too large"
+// @Failure 415 {object} map[string]string "Unsupported image type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/image [post]
+func (ic *ItemController) UploadImage(c *gin.Context) {
+	itemID, userID, data, ok := ic.readImageUpload(c)
+	if !ok {
+		return
+	}
+	if _, err := ic.itemService.AddImage(c.Request.Context(), itemID, userID, data, "", true); err != nil {
+		respondImageError(c, "item image upload failed", itemID, userID, err)
+		return
+	}
+	item, err := ic.itemService.GetItem(c.Request.Context(), itemID, userID)
+	if err != nil {
+		utils.Logger.Error("get item after image upload failed", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("item image uploaded", zap.String("item_id", itemID.String()), zap.String("user_id", userID.String()), zap.Int("bytes", len(data)))
+	c.JSON(http.StatusOK, item)
+}
+
+// AddImage godoc
+// @Summary Add an image to an item
+// @Description Appends a JPEG, PNG or WebP image (max 10 MB, up to 20 per item) after the item's existing images and generates a thumbnail. The item's first image always becomes primary.
+// @Tags items
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param image formData file true "Image file"
+// @Param caption formData string false "Caption, e.g. the item's condition"
+// @Param primary formData bool false "Make this the primary image"
+// @Success 201 {object} dto.ItemImageDTO
+// @Failure 400 {object} map[string]string "Invalid item ID, missing file, caption too long, undecodable image or image over 40 megapixels"
+// @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 409 {object} map[string]string "Item already has the maximum number of images"
+// @Failure 413 {object} map[string]string "Image too large"
+// @Failure 415 {object} map[string]string "Unsupported image type"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/images [post]
+func (ic *ItemController) AddImage(c *gin.Context) {
+	itemID, userID, data, ok := ic.readImageUpload(c)
+	if !ok {
+		return
+	}
+	caption := c.PostForm("caption")
+	if len(caption) > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "caption must be at most 200 characters"})
+		return
+	}
+	primary := c.PostForm("primary") == "true"
+
+	image, err := ic.itemService.AddImage(c.Request.Context(), itemID, userID, data, caption, primary)
+	if err != nil {
+		respondImageError(c, "add item image failed", itemID, userID, err)
+		return
+	}
+	utils.Logger.Info("item image added", zap.String("item_id", itemID.String()), zap.String("image_id", image.ID.String()), zap.String("user_id", userID.String()), zap.Int("bytes", len(data)))
+	c.JSON(http.StatusCreated, image)
+}
+
+// UpdateImage godoc
+// @Summary Update an item image
+// @Description Changes the caption of an image or makes it the item's primary image
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param image_id path string true "Image ID"
+// @Param body body dto.UpdateItemImageRequest true "Fields to update"
+// @Success 200 {object} dto.ItemImageDTO
+// @Failure 400 {object} map[string]string "Invalid ID or payload"
+// @Failure 404 {object} map[string]string "Item or image not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/images/{image_id} [patch]
+func (ic *ItemController) UpdateImage(c *gin.Context) {
+	itemID, imageID, ok := parseItemImageIDs(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateItemImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("invalid item image update payload", zap.String("image_id", imageID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+	image, err := ic.itemService.UpdateImage(c.Request.Context(), itemID, imageID, userID, req)
+	if err != nil {
+		respondImageError(c, "update item image failed", itemID, userID, err)
+		return
+	}
+	utils.Logger.Info("item image updated", zap.String("item_id", itemID.String()), zap.String("image_id", imageID.String()), zap.String("user_id", userID.String()))
+	c.JSON(http.StatusOK, image)
+}
+
+// ReorderImages godoc
+// @Summary Reorder item images
+// @Description Sets the display order of the item's images. image_ids must list every image of the item exactly once.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param body body dto.ReorderItemImagesRequest true "Image IDs in the new order"
+// @Success 200 {object} map[string][]dto.ItemImageDTO "Images in their new order"
+// @Failure 400 {object} map[string]string "Invalid item ID or image list"
+// @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/images/order [put]
+func (ic *ItemController) ReorderImages(c *gin.Context) {
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid item ID", zap.String("item_id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+	var req dto.ReorderItemImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("invalid item image order payload", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+	images, err := ic.itemService.ReorderImages(c.Request.Context(), itemID, userID, req.ImageIDs)
+	if err != nil {
+		respondImageError(c, "reorder item images failed", itemID, userID, err)
+		return
+	}
+	utils.Logger.Info("item images reordered", zap.String("item_id", itemID.String()), zap.String("user_id", userID.String()))
+	c.JSON(http.StatusOK, gin.H{"images": images})
+}
+
+// DeleteImage godoc
+// @Summary Delete an item image
+// @Description Deletes the image. If it was primary, the next image in order becomes primary.
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} map[string]string "Image deleted successfully"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 404 {object} map[string]string "Item or image not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/images/{image_id} [delete]
+func (ic *ItemController) DeleteImage(c *gin.Context) {
+	itemID, imageID, ok := parseItemImageIDs(c)
+	if !ok {
+		return
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+	if err := ic.itemService.DeleteImage(c.Request.Context(), itemID, imageID, userID); err != nil {
+		respondImageError(c, "delete item image failed", itemID, userID, err)
+		return
+	}
+	utils.Logger.Info("item image deleted", zap.String("item_id", itemID.String()), zap.String("image_id", imageID.String()), zap.String("user_id", userID.String()))
+	c.JSON(http.StatusOK, gin.H{"message": "Image deleted successfully"})
+}
+
+// readImageUpload parses the item ID and reads the multipart "image" file,
+// writing the error response itself when something is wrong.
+func (ic *ItemController) readImageUpload(c *gin.Context) (uuid.UUID, uuid.UUID, []byte, bool) {
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid item ID", zap.String("item_id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return uuid.Nil, uuid.Nil, nil, false
+	}
+	userID := c.MustGet("user_id").(uuid.UUID)
+	if err := assertItemOwnership(ic.itemService, c, itemID, userID); err != nil {
+		return uuid.Nil, uuid.Nil, nil, false
+	}
+
+	// Leave some room for the multipart envelope around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, usecase.MaxItemImageBytes+1<<20)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": usecase.ErrImageTooLarge.Error()})
+			return uuid.Nil, uuid.Nil, nil, false
+		}
+		utils.Logger.Warn("missing image upload", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image file is required"})
+		return uuid.Nil, uuid.Nil, nil, false
+	}
+	if header.Size > usecase.MaxItemImageBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": usecase.ErrImageTooLarge.Error()})
+		return uuid.Nil, uuid.Nil, nil, false
+	}
+	file, err := header.Open()
+	if err != nil {
+		utils.Logger.Error("failed to open uploaded image", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, nil, false
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		utils.Logger.Error("failed to read uploaded image", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, nil, false
+	}
+	return itemID, userID, data, true
+}
+
+func parseItemImageIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid item ID", zap.String("item_id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	imageID, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		utils.Logger.Warn("invalid image ID", zap.String("image_id", c.Param("image_id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid image ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return itemID, imageID, true
+}
+
+func respondImageError(c *gin.Context, msg string, itemID, userID uuid.UUID, err error) {
+	status := imageErrorStatus(err)
+	fields := []zap.Field{zap.String("item_id", itemID.String()), zap.String("user_id", userID.String()), zap.Error(err)}
+	if status == http.StatusInternalServerError {
+		utils.Logger.Error(msg, fields...)
+	} else {
+		utils.Logger.Warn(msg, fields...)
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
 // DeleteItem godoc
 // @Summary Delete an item
 // @Description Deletes the item if it exists and belongs to the user
 // @Tags items
 // @Produce json
 // @Param id path string true "Item ID"
+// @Param If-Match header string false "ETag from GET /items/{id}; the item is only deleted at that version"
 // @Success 200 {object} map[string]string "Item deleted successfully"
 // @Failure 400 {object} map[string]string "Invalid item ID"
 // @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 412 {object} map[string]string "Item changed since the If-Match version"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /items/{id} [delete]
 func (ic *ItemController) DeleteItem(c *gin.Context) {
@@ -199,7 +707,15 @@ func (ic *ItemController) DeleteItem(c *gin.Context) {
 	if err := assertItemOwnership(ic.itemService, c, itemID, userID); err != nil {
 		return
 	}
-	if err := ic.itemService.DeleteItem(c.Request.Context(), itemID, userID); err != nil {
+	ifMatch, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	if err := ic.itemService.DeleteItem(c.Request.Context(), itemID, userID, ifMatch); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			c.JSON(versionConflictStatus(c), gin.H{"error": err.Error()})
+			return
+		}
 		utils.Logger.Error("item deletion failed", zap.String("item_id", itemID.String()), zap.String("user_id", userID.String()), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -208,6 +724,208 @@ func (ic *ItemController) DeleteItem(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Item deleted successfully"})
 }
 
+// MoveItems godoc
+// @Summary Move items to another box
+// @Description Move items from this sort-packed box to another sort-packed box of the same user. All items move or none do.
+// @Tags items
+// @Accept json
+// @Produce json
+// @Param id path string true "Box ID or code"
+// @Param body body dto.MoveItemsRequest true "Items to move and the target box ID or code"
+// @Success 200 {object} map[string]string "Items moved"
+// @Failure 400 {object} map[string]string "Invalid payload, or a box is not sort-packed or already returned"
+// @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 409 {object} map[string]string "Some items are not in the box"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/{id}/items/move [post]
+func (ic *ItemController) MoveItems(c *gin.Context) {
+	boxID, ok := parseMoveBoxID(c)
+	if !ok {
+		return
+	}
+	var req dto.MoveItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("invalid item move payload", zap.String("box_id", boxID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	if err := ic.itemService.MoveItems(c.Request.Context(), userID, boxID, req); err != nil {
+		respondMoveError(c, "item move failed", boxID, userID, err)
+		return
+	}
+	utils.Logger.Info("items moved",
+		zap.String("box_id", boxID.String()),
+		zap.String("target_box", req.TargetBox),
+		zap.Int("count", len(req.ItemIDs)))
+	c.JSON(http.StatusOK, gin.H{"message": "Items moved"})
+}
+
+// MergeBoxes godoc
+// @Summary Merge another box into this one
+// @Description Move every item of the source box into this box and delete the emptied source box, freeing its storage slot. Both must be sort-packed boxes of the same user.
+// @Tags boxes
+// @Accept json
+// @Produce json
+// @Param id path string true "Box ID or code"
+// @Param body body dto.MergeBoxesRequest true "ID or code of the box to merge into this one"
+// @Success 200 {object} map[string]string "Boxes merged"
+// @Failure 400 {object} map[string]string "Invalid payload, or a box is not sort-packed or already returned"
+// @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/{id}/merge [post]
+func (ic *ItemController) MergeBoxes(c *gin.Context) {
+	boxID, ok := parseMoveBoxID(c)
+	if !ok {
+		return
+	}
+	var req dto.MergeBoxesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("invalid box merge payload", zap.String("box_id", boxID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	if err := ic.itemService.MergeBoxes(c.Request.Context(), userID, boxID, req); err != nil {
+		respondMoveError(c, "box merge failed", boxID, userID, err)
+		return
+	}
+	utils.Logger.Info("boxes merged",
+		zap.String("box_id", boxID.String()),
+		zap.String("source_box", req.SourceBox))
+	c.JSON(http.StatusOK, gin.H{"message": "Boxes merged"})
+}
+
+// SplitBox godoc
+// @Summary Split items off into a new box
+// @Description Move items from this sort-packed box into a new box with the same status and storage location.
+// @Tags boxes
+// @Accept json
+// @Produce json
+// @Param id path string true "Box ID or code"
+// @Param body body dto.SplitBoxRequest true "Items for the new box"
+// @Success 201 {object} dto.SplitBoxResponse
+// @Failure 400 {object} map[string]string "Invalid payload, or the box is not sort-packed or already returned"
+// @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 409 {object} map[string]string "Some items are not in the box, or the location is full"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/{id}/split [post]
+func (ic *ItemController) SplitBox(c *gin.Context) {
+	boxID, ok := parseMoveBoxID(c)
+	if !ok {
+		return
+	}
+	var req dto.SplitBoxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("invalid box split payload", zap.String("box_id", boxID.String()), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	box, err := ic.itemService.SplitBox(c.Request.Context(), userID, boxID, req)
+	if err != nil {
+		respondMoveError(c, "box split failed", boxID, userID, err)
+		return
+	}
+	utils.Logger.Info("box split",
+		zap.String("box_id", boxID.String()),
+		zap.String("new_box_id", box.ID.String()),
+		zap.Int("count", len(req.ItemIDs)))
+	c.JSON(http.StatusCreated, box)
+}
+
+// GetItemHistory godoc
+// @Summary Get item history
+// @Description Lists every move of the item between boxes, oldest first
+// @Tags items
+// @Produce json
+// @Param id path string true "Item ID"
+// @Success 200 {object} map[string][]dto.ItemMoveResponse
+// @Failure 400 {object} map[string]string "Invalid item ID"
+// @Failure 404 {object} map[string]string "Item not found or inaccessible"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /items/{id}/history [get]
+func (ic *ItemController) GetItemHistory(c *gin.Context) {
+	itemID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid item ID", zap.String("id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item ID"})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	history, err := ic.itemService.GetItemHistory(c.Request.Context(), itemID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "item not found or not accessible"})
+		return
+	}
+	if err != nil {
+		utils.Logger.Error("failed to get item history", zap.String("item_id", itemID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+func parseMoveBoxID(c *gin.Context) (uuid.UUID, bool) {
+	boxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("invalid box ID", zap.String("id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid box ID"})
+		return uuid.Nil, false
+	}
+	return boxID, true
+}
+
+func respondMoveError(c *gin.Context, msg string, boxID, userID uuid.UUID, err error) {
+	status := moveErrorStatus(err)
+	fields := []zap.Field{zap.String("box_id", boxID.String()), zap.String("user_id", userID.String()), zap.Error(err)}
+	if status == http.StatusInternalServerError {
+		utils.Logger.Error(msg, fields...)
+	} else {
+		utils.Logger.Warn(msg, fields...)
+	}
+	if status == http.StatusNotFound {
+		c.JSON(status, gin.H{"error": "box not found or not accessible"})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func moveErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidBoxCode), errors.Is(err, usecase.ErrSameBox),
+		errors.Is(err, usecase.ErrBoxNotSortPacked), errors.Is(err, usecase.ErrBoxClosed):
+		return http.StatusBadRequest
+	case errors.Is(err, repository.ErrItemsNotInBox), errors.Is(err, repository.ErrLocationFull):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func imageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrImageTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, usecase.ErrUnsupportedImageType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, usecase.ErrInvalidImage), errors.Is(err, usecase.ErrImageDimensions), errors.Is(err, repository.ErrImageOrderMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrTooManyImages):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func assertItemOwnership(service *usecase.ItemService, c *gin.Context, itemID, userID uuid.UUID) error {
 	_, err := service.GetItem(c.Request.Context(), itemID, userID)
 	if err != nil {
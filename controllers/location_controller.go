@@ -0,0 +1,273 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/usecase"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type LocationController struct {
+	service *usecase.LocationService
+}
+
+func NewLocationController(service *usecase.LocationService) *LocationController {
+	return &LocationController{service: service}
+}
+
+// CreateLocation godoc
+// @Summary Create a storage location
+// @Description Create a new warehouse location. Requires location:manage.
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateLocationRequest true "Location data"
+// @Success 201 {object} map[string]string "ID of the created location"
+// @Failure 400 {object} map[string]string "Invalid payload"
+// @Failure 403 {object} map[string]string "Permission location:manage required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /locations [post]
+func (lc *LocationController) CreateLocation(c *gin.Context) {
+	var req dto.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid location creation input", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	locationID, err := lc.service.CreateLocation(c.Request.Context(), req)
+	if err != nil {
+		utils.Logger.Error("Location creation failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Logger.Info("Location successfully created",
+		zap.String("location_id", locationID.String()),
+		zap.Int("capacity", req.Capacity))
+	c.JSON(http.StatusCreated, gin.H{"id": locationID})
+}
+
+// ListLocations godoc
+// @Summary List storage locations
+// @Description Get all warehouse locations with their capacity and current load. Requires location:read.
+// @Tags locations
+// @Produce json
+// @Success 200 {object} map[string][]dto.LocationResponse
+// @Failure 403 {object} map[string]string "Permission location:read required"
+// @Failure 500 {object} map[string]string
+// @Router /locations [get]
+func (lc *LocationController) ListLocations(c *gin.Context) {
+	locations, err := lc.service.ListLocations(c.Request.Context())
+	if err != nil {
+		utils.Logger.Error("Failed to list locations", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"locations": locations})
+}
+
+// GetLocation godoc
+// @Summary Get a storage location by ID
+// @Description Requires location:read.
+// @Tags locations
+// @Produce json
+// @Param id path string true "Location ID"
+// @Success 200 {object} map[string]dto.LocationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "Permission location:read required"
+// @Failure 404 {object} map[string]string
+// @Router /locations/{id} [get]
+func (lc *LocationController) GetLocation(c *gin.Context) {
+	locationID, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+	location, err := lc.service.GetLocation(c.Request.Context(), locationID)
+	if err != nil {
+		utils.Logger.Warn("Location not found",
+			zap.String("location_id", locationID.String()),
+			zap.Error(err))
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"location": location})
+}
+
+// UpdateLocation godoc
+// @Summary Update a storage location
+// @Description Update name, address or capacity. Capacity cannot drop below the current load. Requires location:manage.
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Param id path string true "Location ID"
+// @Param body body dto.UpdateLocationRequest true "Fields to update"
+// @Success 200 {object} map[string]string "Location updated successfully"
+// @Failure 400 {object} map[string]string "Invalid location ID or payload"
+// @Failure 403 {object} map[string]string "Permission location:manage required"
+// @Failure 404 {object} map[string]string "Location not found"
+// @Failure 409 {object} map[string]string "Capacity below current load"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /locations/{id} [patch]
+func (lc *LocationController) UpdateLocation(c *gin.Context) {
+	locationID, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid location update payload",
+			zap.String("location_id", locationID.String()),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := lc.service.UpdateLocation(c.Request.Context(), locationID, req); err != nil {
+		utils.Logger.Warn("Location update failed",
+			zap.String("location_id", locationID.String()),
+			zap.Error(err))
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("Location updated successfully", zap.String("location_id", locationID.String()))
+	c.JSON(http.StatusOK, gin.H{"message": "Location updated successfully"})
+}
+
+// DeleteLocation godoc
+// @Summary Delete a storage location
+// @Description Delete an empty location. Requires location:manage.
+// @Tags locations
+// @Produce json
+// @Param id path string true "Location ID"
+// @Success 200 {object} map[string]string "Location deleted successfully"
+// @Failure 400 {object} map[string]string "Invalid location ID"
+// @Failure 403 {object} map[string]string "Permission location:manage required"
+// @Failure 404 {object} map[string]string "Location not found"
+// @Failure 409 {object} map[string]string "Location still holds boxes"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /locations/{id} [delete]
+func (lc *LocationController) DeleteLocation(c *gin.Context) {
+	locationID, ok := parseLocationID(c)
+	if !ok {
+		return
+	}
+	if err := lc.service.DeleteLocation(c.Request.Context(), locationID); err != nil {
+		utils.Logger.Warn("Location deletion failed",
+			zap.String("location_id", locationID.String()),
+			zap.Error(err))
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("Location deleted", zap.String("location_id", locationID.String()))
+	c.JSON(http.StatusOK, gin.H{"message": "Location deleted"})
+}
+
+// PlaceBox godoc
+// @Summary Place a box at a storage location
+// @Description Assign a box to a location or move it from its current one. Requires box:place.
+// @Tags locations
+// @Accept json
+// @Produce json
+// @Param id path string true "Box ID or code"
+// @Param body body dto.PlaceBoxRequest true "Target location"
+// @Success 200 {object} map[string]string "Box placed successfully"
+// @Failure 400 {object} map[string]string "Invalid box ID, payload or box status"
+// @Failure 403 {object} map[string]string "Permission box:place required"
+// @Failure 404 {object} map[string]string "Box or location not found"
+// @Failure 409 {object} map[string]string "Location is at capacity"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/{id}/location [put]
+func (lc *LocationController) PlaceBox(c *gin.Context) {
+	boxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid box ID for placement",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid box ID"})
+		return
+	}
+	var req dto.PlaceBoxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid box placement payload",
+			zap.String("box_id", boxID.String()),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := lc.service.PlaceBox(c.Request.Context(), boxID, req.LocationID); err != nil {
+		utils.Logger.Warn("Box placement failed",
+			zap.String("box_id", boxID.String()),
+			zap.String("location_id", req.LocationID.String()),
+			zap.Error(err))
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("Box placed",
+		zap.String("box_id", boxID.String()),
+		zap.String("location_id", req.LocationID.String()))
+	c.JSON(http.StatusOK, gin.H{"message": "Box placed"})
+}
+
+// RemoveBox godoc
+// @Summary Remove a box from its storage location
+// @Description Clear the box's location and release its slot. Requires box:place.
+// @Tags locations
+// @Produce json
+// @Param id path string true "Box ID or code"
+// @Success 200 {object} map[string]string "Box removed from location"
+// @Failure 400 {object} map[string]string "Invalid box ID"
+// @Failure 403 {object} map[string]string "Permission box:place required"
+// @Failure 404 {object} map[string]string "Box not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /boxes/{id}/location [delete]
+func (lc *LocationController) RemoveBox(c *gin.Context) {
+	boxID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid box ID for removal",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid box ID"})
+		return
+	}
+	if err := lc.service.RemoveBox(c.Request.Context(), boxID); err != nil {
+		utils.Logger.Warn("Box removal from location failed",
+			zap.String("box_id", boxID.String()),
+			zap.Error(err))
+		c.JSON(locationErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("Box removed from location", zap.String("box_id", boxID.String()))
+	c.JSON(http.StatusOK, gin.H{"message": "Box removed from location"})
+}
+
+func parseLocationID(c *gin.Context) (uuid.UUID, bool) {
+	locationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid location ID format",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid location ID"})
+		return uuid.Nil, false
+	}
+	return locationID, true
+}
+
+func locationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrLocationFull), errors.Is(err, repository.ErrLocationInUse):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrBoxNotPlaceable):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
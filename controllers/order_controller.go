@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/middleware"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/usecase"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type OrderController struct {
+	service *usecase.OrderService
+}
+
+func NewOrderController(service *usecase.OrderService) *OrderController {
+	return &OrderController{service: service}
+}
+
+// CreateOrder godoc
+// @Summary Schedule a storage order
+// @Description Schedule a pickup or return for one of the user's boxes. Scheduling for other users' boxes requires order:create_any and relocate orders require order:relocate.
+// @Description A return of a sort-packed box may list items and quantities to get back only those; the box then stays stored.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateOrderRequest true "Order data"
+// @Success 201 {object} map[string]string "ID of the created order"
+// @Failure 400 {object} map[string]string "Invalid payload, box status or requested items"
+// @Failure 403 {object} map[string]string "Permission order:relocate required"
+// @Failure 404 {object} map[string]string "Box not found or inaccessible"
+// @Failure 409 {object} map[string]string "Box already has an open order"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders [post]
+func (oc *OrderController) CreateOrder(c *gin.Context) {
+	var req dto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid order creation input", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := middleware.CurrentPrincipal(c)
+	userID := principal.UserID
+	orderID, err := oc.service.CreateOrder(c.Request.Context(), principal, req)
+	if err != nil {
+		utils.Logger.Warn("Order creation failed",
+			zap.String("user_id", userID.String()),
+			zap.String("box_id", req.BoxID.String()),
+			zap.Error(err))
+		c.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Logger.Info("Order successfully created",
+		zap.String("order_id", orderID.String()),
+		zap.String("box_id", req.BoxID.String()),
+		zap.String("type", req.Type),
+		zap.String("user_id", userID.String()))
+	c.JSON(http.StatusCreated, gin.H{"id": orderID})
+}
+
+// ListOrders godoc
+// @Summary List storage orders
+// @Description Customers get their own orders. Employees get every order, optionally filtered by status.
+// @Tags orders
+// @Produce json
+// @Param status query string false "Filter by status (with order:read_any only)"
+// @Success 200 {object} map[string][]dto.OrderResponse
+// @Failure 500 {object} map[string]string
+// @Router /orders [get]
+func (oc *OrderController) ListOrders(c *gin.Context) {
+	principal := middleware.CurrentPrincipal(c)
+	userID := principal.UserID
+	orders, err := oc.service.ListOrders(c.Request.Context(), principal, c.Query("status"))
+	if err != nil {
+		utils.Logger.Error("Failed to list orders",
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("Orders retrieved successfully",
+		zap.String("user_id", userID.String()),
+		zap.Int("count", len(orders)))
+	c.JSON(http.StatusOK, gin.H{"orders": orders})
+}
+
+// GetOrder godoc
+// @Summary Get a storage order by ID
+// @Description Get a single order. Customers can only view their own orders.
+// @Tags orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} map[string]dto.OrderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /orders/{id} [get]
+func (oc *OrderController) GetOrder(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid order ID format",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+	principal := middleware.CurrentPrincipal(c)
+	userID := principal.UserID
+	order, err := oc.service.GetOrder(c.Request.Context(), orderID, principal)
+	if err != nil {
+		utils.Logger.Warn("Order not found or not accessible",
+			zap.String("order_id", orderID.String()),
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		c.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"order": order})
+}
+
+// UpdateOrderStatus godoc
+// @Summary Update storage order status
+// @Description Move an order through its lifecycle (requested → in_progress → completed, or cancelled). Customers can only cancel their own requested orders.
+// @Description Completing a partial return reduces the quantities of the returned items.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param body body dto.UpdateOrderStatusRequest true "New status"
+// @Success 200 {object} map[string]string "Order status updated successfully"
+// @Failure 400 {object} map[string]string "Invalid order ID or status"
+// @Failure 403 {object} map[string]string "Permission order:assign required"
+// @Failure 404 {object} map[string]string "Order not found or inaccessible"
+// @Failure 409 {object} map[string]string "Transition not allowed from current status, order changed meanwhile, or returned items are no longer in the box"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/{id}/status [patch]
+func (oc *OrderController) UpdateOrderStatus(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid order ID for status update",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid order status payload",
+			zap.String("order_id", orderID.String()),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := middleware.CurrentPrincipal(c)
+	userID := principal.UserID
+	if err := oc.service.UpdateStatus(c.Request.Context(), orderID, principal, req.Status); err != nil {
+		utils.Logger.Warn("Failed to update order status",
+			zap.String("order_id", orderID.String()),
+			zap.String("user_id", userID.String()),
+			zap.Error(err))
+		c.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	utils.Logger.Info("Order status updated successfully",
+		zap.String("order_id", orderID.String()),
+		zap.String("new_status", req.Status))
+	c.JSON(http.StatusOK, gin.H{"message": "Order status updated"})
+}
+
+// GetPickList godoc
+// @Summary Get the pick list of a return order
+// @Description Requires order:assign. Shows where the box is and which items to take out of it, or every item for whole-box returns.
+// @Tags orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.PickListResponse
+// @Failure 400 {object} map[string]string "Invalid order ID or not a return order"
+// @Failure 403 {object} map[string]string "Permission order:assign required"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/{id}/pick-list [get]
+func (oc *OrderController) GetPickList(c *gin.Context) {
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		utils.Logger.Warn("Invalid order ID format",
+			zap.String("id", c.Param("id")),
+			zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order ID"})
+		return
+	}
+	pickList, err := oc.service.PickList(c.Request.Context(), orderID, middleware.CurrentPrincipal(c))
+	if err != nil {
+		utils.Logger.Warn("Failed to build pick list",
+			zap.String("order_id", orderID.String()),
+			zap.Error(err))
+		c.JSON(orderErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pickList)
+}
+
+func orderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrOrderNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, policy.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrOrderAlreadyOpen), errors.Is(err, usecase.ErrOrderInvalidTransition),
+		errors.Is(err, repository.ErrVersionConflict), errors.Is(err, repository.ErrItemsNotInBox),
+		errors.Is(err, repository.ErrNegativeQuantity):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrOrderBoxState), errors.Is(err, usecase.ErrOrderDateInPast),
+		errors.Is(err, usecase.ErrOrderItemsNotReturn), errors.Is(err, usecase.ErrWholeBoxReturnOnly),
+		errors.Is(err, usecase.ErrOrderItemDuplicate), errors.Is(err, usecase.ErrOrderItemNotInBox),
+		errors.Is(err, usecase.ErrOrderItemQuantity), errors.Is(err, usecase.ErrPickListNotAvailable):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/usecase"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+)
+
+type RevocationController struct {
+	service *usecase.TokenRevocationService
+}
+
+func NewRevocationController(service *usecase.TokenRevocationService) *RevocationController {
+	return &RevocationController{service: service}
+}
+
+// RevokeToken godoc
+// @Summary Revoke an access token
+// @Description Internal endpoint for the user-service. Rejects the token with the given jti until it expires. Requires a service API key with the token:revoke scope.
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Service API key"
+// @Param body body dto.RevokeTokenRequest true "Token to revoke"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Invalid payload"
+// @Failure 401 {object} map[string]string "Invalid, expired or revoked API key"
+// @Failure 403 {object} map[string]string "Permission token:revoke required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /internal/revocations/tokens [post]
+func (rc *RevocationController) RevokeToken(c *gin.Context) {
+	var req dto.RevokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid token revocation input", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rc.service.RevokeToken(c.Request.Context(), req); err != nil {
+		utils.Logger.Error("Token revocation failed", zap.String("jti", req.JTI), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Logger.Info("Token revoked",
+		zap.String("jti", req.JTI),
+		zap.Time("expires_at", req.ExpiresAt))
+	c.JSON(http.StatusOK, gin.H{"message": "Token revoked"})
+}
+
+// RevokeUserTokens godoc
+// @Summary Revoke all access tokens of a user
+// @Description Internal endpoint for the user-service, e.g. after a ban or a log out everywhere. Rejects every token of the user issued before issued_before (default now); tokens issued within the same second count as issued before. Requires a service API key with the token:revoke scope.
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Service API key"
+// @Param body body dto.RevokeUserTokensRequest true "User whose tokens to revoke"
+// @Success 200 {object} map[string]string "Applied cutoff as revoked_before"
+// @Failure 400 {object} map[string]string "Invalid payload"
+// @Failure 401 {object} map[string]string "Invalid, expired or revoked API key"
+// @Failure 403 {object} map[string]string "Permission token:revoke required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /internal/revocations/users [post]
+func (rc *RevocationController) RevokeUserTokens(c *gin.Context) {
+	var req dto.RevokeUserTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid user token revocation input", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before, err := rc.service.RevokeUserTokens(c.Request.Context(), req)
+	if err != nil {
+		utils.Logger.Error("User token revocation failed", zap.String("user_id", req.UserID.String()), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Logger.Info("User tokens revoked",
+		zap.String("user_id", req.UserID.String()),
+		zap.Time("revoked_before", before))
+	c.JSON(http.StatusOK, gin.H{"revoked_before": before.UTC().Format(time.RFC3339Nano)})
+}
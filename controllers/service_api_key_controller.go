@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/middleware"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/usecase"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ServiceAPIKeyController struct {
+	service *usecase.ServiceAPIKeyService
+}
+
+func NewServiceAPIKeyController(service *usecase.ServiceAPIKeyService) *ServiceAPIKeyController {
+	return &ServiceAPIKeyController{service: service}
+}
+
+// CreateAPIKey godoc
+// @Summary Issue a service API key
+// @Description Issue a key that lets another service call the API with the X-API-Key header instead of a user token. Scopes are permission names, e.g. "order:read_any" or "token:revoke". The key is only returned in this response. Requires api_key:manage.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateServiceAPIKeyRequest true "Key name, scopes and optional expiry"
+// @Success 201 {object} map[string]dto.CreatedServiceAPIKeyResponse
+// @Failure 400 {object} map[string]string "Invalid payload, unknown scope or expiry in the past"
+// @Failure 403 {object} map[string]string "Permission api_key:manage, or a scope the caller lacks, required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/api-keys [post]
+func (kc *ServiceAPIKeyController) CreateAPIKey(c *gin.Context) {
+	var req dto.CreateServiceAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.Logger.Warn("Invalid API key creation input", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	principal := middleware.CurrentPrincipal(c)
+	key, err := kc.service.CreateKey(c.Request.Context(), principal, req)
+	if err != nil {
+		utils.Logger.Warn("API key creation failed", zap.String("name", req.Name), zap.Error(err))
+		c.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Logger.Info("Service API key issued",
+		zap.String("api_key_id", key.ID.String()),
+		zap.String("name", key.Name),
+		zap.Strings("scopes", key.Scopes),
+		zap.String("created_by", principal.UserID.String()))
+	c.JSON(http.StatusCreated, gin.H{"api_key": key})
+}
+
+// ListAPIKeys godoc
+// @Summary List service API keys
+// @Description List all issued keys, including expired and revoked ones, without the keys themselves. Requires api_key:manage.
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} map[string][]dto.ServiceAPIKeyResponse
+// @Failure 403 {object} map[string]string "Permission api_key:manage required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/api-keys [get]
+func (kc *ServiceAPIKeyController) ListAPIKeys(c *gin.Context) {
+	keys, err := kc.service.ListKeys(c.Request.Context())
+	if err != nil {
+		utils.Logger.Error("Failed to list API keys", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke a service API key
+// @Description The key is rejected from the next request on. Requires api_key:manage.
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} map[string]dto.ServiceAPIKeyResponse
+// @Failure 400 {object} map[string]string "Invalid API key ID"
+// @Failure 403 {object} map[string]string "Permission api_key:manage required"
+// @Failure 404 {object} map[string]string "API key not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/api-keys/{id} [delete]
+func (kc *ServiceAPIKeyController) RevokeAPIKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key ID"})
+		return
+	}
+
+	key, err := kc.service.RevokeKey(c.Request.Context(), keyID)
+	if err != nil {
+		utils.Logger.Warn("API key revocation failed", zap.String("api_key_id", keyID.String()), zap.Error(err))
+		c.JSON(apiKeyErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	utils.Logger.Info("Service API key revoked",
+		zap.String("api_key_id", key.ID.String()),
+		zap.String("name", key.Name),
+		zap.String("revoked_by", middleware.CurrentPrincipal(c).UserID.String()))
+	c.JSON(http.StatusOK, gin.H{"api_key": key})
+}
+
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrUnknownScope), errors.Is(err, usecase.ErrAPIKeyExpiresAt):
+		return http.StatusBadRequest
+	case errors.Is(err, policy.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,25 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is the envelope delivered to other services for every outbox entry.
+type Event struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Publisher delivers events to downstream consumers. Delivery is at-least-once,
+// so consumers must deduplicate on Event.ID.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
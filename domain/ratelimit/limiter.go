@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests requests per fixed Window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Window)
+}
+
+// Result is the outcome of counting one request against a Limit.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the current window ends and the count restarts
+	ResetAfter time.Duration
+}
+
+// Limiter counts requests per key in fixed windows. Implementations sharing a
+// backend share the counts, so a limit holds across replicas.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Rules picks the limit for a route group and account type. The most specific
+// rule wins: "group:account_type", then "group", then "*:account_type", then "*".
+type Rules struct {
+	limits map[string]Limit
+}
+
+// ParseRules reads a comma-separated list of scope=requests/window rules, e.g.
+// "*=120/1m,*:customer=60/1m,admin=30/1m,boxes:warehouse_staff=600/1m".
+// The "*" rule is required so that every request has a limit.
+func ParseRules(spec string) (*Rules, error) {
+	rules := &Rules{limits: make(map[string]Limit)}
+	for _, rule := range strings.Split(spec, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		scope, value, ok := strings.Cut(rule, "=")
+		if !ok || strings.TrimSpace(scope) == "" {
+			return nil, fmt.Errorf("rate limit rule %q: expected scope=requests/window", rule)
+		}
+		requests, window, ok := strings.Cut(value, "/")
+		if !ok {
+			return nil, fmt.Errorf("rate limit rule %q: expected requests/window", rule)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(requests))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("rate limit rule %q: requests must be a positive number", rule)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(window))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("rate limit rule %q: window must be a duration of at least 1s", rule)
+		}
+		rules.limits[strings.TrimSpace(scope)] = Limit{Requests: n, Window: d}
+	}
+	if _, ok := rules.limits["*"]; !ok {
+		return nil, fmt.Errorf("rate limits %q have no default \"*\" rule", spec)
+	}
+	return rules, nil
+}
+
+// For returns the limit for requests to group by accountType.
+func (r *Rules) For(group, accountType string) Limit {
+	for _, scope := range []string{group + ":" + accountType, group, "*:" + accountType} {
+		if limit, ok := r.limits[scope]; ok {
+			return limit
+		}
+	}
+	return r.limits["*"]
+}
+
+// Window returns the start of the fixed window of length window holding now,
+// and the time until it ends.
+func Window(now time.Time, window time.Duration) (start time.Time, resetAfter time.Duration) {
+	start = now.Truncate(window)
+	return start, start.Add(window).Sub(now)
+}
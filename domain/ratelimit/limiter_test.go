@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("*=120/1m, *:customer=60/1m, admin=30/1m, boxes:warehouse_staff=600/1m")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, Limit{Requests: 600, Window: time.Minute}, rules.For("boxes", "warehouse_staff"))
+	assert.Equal(t, Limit{Requests: 120, Window: time.Minute}, rules.For("orders", "warehouse_staff"))
+	assert.Equal(t, Limit{Requests: 60, Window: time.Minute}, rules.For("boxes", "customer"))
+	// A group rule beats an account type rule
+	assert.Equal(t, Limit{Requests: 30, Window: time.Minute}, rules.For("admin", "customer"))
+	assert.Equal(t, Limit{Requests: 120, Window: time.Minute}, rules.For("items", "anonymous"))
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"boxes=10/1m",
+		"*=10",
+		"*=0/1m",
+		"*=ten/1m",
+		"*=10/1ms",
+		"=10/1m",
+	} {
+		_, err := ParseRules(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 45, 0, time.UTC)
+	start, resetAfter := Window(now, time.Minute)
+	assert.Equal(t, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, 15*time.Second, resetAfter)
+}
@@ -2,16 +2,65 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
+
 	"github.com/google/uuid"
-	"github.com/sandroJayas/storage-service/dto"
 	"github.com/sandroJayas/storage-service/models"
 )
 
+// ErrBoxCodeTaken is returned by Create when another box already has the code.
+var ErrBoxCodeTaken = errors.New("box code already in use")
+
 type BoxRepository interface {
 	Create(ctx context.Context, box *models.Box) error
 	FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Box, error)
-	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.Box, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	SoftDelete(ctx context.Context, id uuid.UUID) error
-	UpdateItem(ctx context.Context, boxID, itemID uuid.UUID, req dto.UpdateItemRequest) error
+	FindAnyByID(ctx context.Context, id uuid.UUID) (*models.Box, error)
+	// FindIDByCode resolves a box code to the box ID, regardless of the owner.
+	FindIDByCode(ctx context.Context, code string) (uuid.UUID, error)
+	// FindAnyByIDs returns the boxes that exist among ids, without their items.
+	FindAnyByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Box, error)
+	FindPage(ctx context.Context, filter BoxFilter, page BoxPage) ([]models.Box, error)
+	Count(ctx context.Context, filter BoxFilter) (int64, error)
+	UpdateStatus(ctx context.Context, event *models.BoxStatusEvent, version int) error
+	UpdateStatusAndLocation(ctx context.Context, event *models.BoxStatusEvent, version int, locationID *uuid.UUID) error
+	FindStatusEvents(ctx context.Context, id uuid.UUID) ([]models.BoxStatusEvent, error)
+	SoftDelete(ctx context.Context, id uuid.UUID, version *int) error
+	// StreamInventory calls fn for each of the user's items, ordered by box, without
+	// loading them all at once. Boxes without items yield one row with a nil ItemID.
+	StreamInventory(ctx context.Context, userID uuid.UUID, fn func(InventoryRow) error) error
+}
+
+// InventoryRow is one item of a user's inventory together with its box.
+type InventoryRow struct {
+	BoxID           uuid.UUID
+	BoxCode         string
+	BoxStatus       string
+	PackingMode     string
+	BoxCreatedAt    time.Time
+	LocationName    *string
+	ItemID          *uuid.UUID
+	ItemName        *string
+	ItemDescription *string
+	ItemQuantity    *int
+}
+
+// BoxFilter narrows a box listing. Zero values are ignored.
+type BoxFilter struct {
+	UserID      *uuid.UUID
+	Status      string
+	PackingMode string
+	LocationID  *uuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// BoxPage selects one keyset page of a box listing ordered by (created_at, id).
+// A zero AfterID starts from the beginning.
+type BoxPage struct {
+	Limit          int
+	Ascending      bool
+	AfterCreatedAt time.Time
+	AfterID        uuid.UUID
+	IncludeItems   bool
 }
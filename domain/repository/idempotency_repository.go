@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+type IdempotencyRepository interface {
+	// Reserve claims record's key for the user. If an unexpired record already
+	// holds the key it is returned instead and nothing is written.
+	Reserve(ctx context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, userID uuid.UUID, key string, status int, contentType string, body []byte) error
+	// Release drops an unfinished reservation so the request can be retried.
+	Release(ctx context.Context, userID uuid.UUID, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
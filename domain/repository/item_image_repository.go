@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+var ErrImageOrderMismatch = errors.New("image_ids must list every image of the item exactly once")
+
+type ItemImageRepository interface {
+	// Create appends the image after the item's existing images. The first image
+	// of an item always becomes its primary image.
+	Create(ctx context.Context, image *models.ItemImage) error
+	ListByItemID(ctx context.Context, itemID uuid.UUID) ([]models.ItemImage, error)
+	FindByID(ctx context.Context, itemID, imageID uuid.UUID) (*models.ItemImage, error)
+	// Update saves caption and primary flag, demoting any other primary image.
+	Update(ctx context.Context, image *models.ItemImage) error
+	Reorder(ctx context.Context, itemID uuid.UUID, imageIDs []uuid.UUID) error
+	// Delete removes the image and promotes the next one if it was primary.
+	Delete(ctx context.Context, itemID, imageID uuid.UUID) (*models.ItemImage, error)
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+var (
+	ErrItemsNotInBox = errors.New("every item must be in the source box")
+	// ErrBoxNotSortPacked and ErrBoxClosed are returned when a box, checked
+	// again once locked, is not sort-packed or has been returned or disposed.
+	ErrBoxNotSortPacked = errors.New("can only add items to sort-packed boxes")
+	ErrBoxClosed        = errors.New("items cannot be moved in or out of returned or disposed boxes")
+)
+
+// ItemMoveRepository moves items between boxes. Every method runs in a single
+// transaction, records an ItemMove per item and bumps the versions of the
+// items and boxes involved. Each returns ErrBoxNotSortPacked or ErrBoxClosed
+// if a box involved no longer accepts moved items.
+type ItemMoveRepository interface {
+	// MoveItems moves the items from one box to another. It returns
+	// ErrItemsNotInBox unless all of them are currently in fromBoxID.
+	MoveItems(ctx context.Context, fromBoxID, toBoxID uuid.UUID, itemIDs []uuid.UUID, actorID uuid.UUID) error
+	// MergeBoxes moves every item of sourceBoxID into targetBoxID, then takes the
+	// emptied source box off its location and deletes it.
+	MergeBoxes(ctx context.Context, targetBoxID, sourceBoxID uuid.UUID, actorID uuid.UUID) error
+	// SplitBox creates newBox, placing it at its LocationID if set, and moves the
+	// items from fromBoxID into it. It returns ErrBoxCodeTaken if newBox.Code is
+	// in use and ErrLocationFull if its location has no room.
+	SplitBox(ctx context.Context, newBox *models.Box, fromBoxID uuid.UUID, itemIDs []uuid.UUID, actorID uuid.UUID) error
+	ListByItemID(ctx context.Context, itemID uuid.UUID) ([]models.ItemMove, error)
+}
@@ -2,14 +2,38 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"github.com/google/uuid"
 	"github.com/sandroJayas/storage-service/models"
 )
 
+var ErrNegativeQuantity = errors.New("quantity cannot drop below zero")
+
 type ItemRepository interface {
 	Create(ctx context.Context, item *models.Item) error
+	// CreateBatch inserts all items in one transaction, or none of them.
+	CreateBatch(ctx context.Context, items []models.Item) error
 	ListByBoxID(ctx context.Context, boxID uuid.UUID) ([]models.Item, error)
 	GetByID(ctx context.Context, itemID uuid.UUID, userID uuid.UUID) (*models.Item, error)
-	Update(ctx context.Context, item *models.Item) error
-	Delete(ctx context.Context, itemID uuid.UUID) error
+	// GetAnyByID looks an item up regardless of who owns its box.
+	GetAnyByID(ctx context.Context, itemID uuid.UUID) (*models.Item, error)
+	// Update saves the item's fields. A changed quantity is recorded in the
+	// quantity ledger as a correction by actorID.
+	Update(ctx context.Context, item *models.Item, actorID uuid.UUID) error
+	// AdjustQuantity adds movement.Delta to the item's quantity and records the
+	// movement. With a version the item must still be at it. It returns
+	// ErrNegativeQuantity if the quantity would drop below zero.
+	AdjustQuantity(ctx context.Context, movement *models.ItemQuantityMovement, version *int) (*models.Item, error)
+	ListQuantityMovements(ctx context.Context, itemID uuid.UUID) ([]models.ItemQuantityMovement, error)
+	Delete(ctx context.Context, itemID uuid.UUID, version *int) error
+	Search(ctx context.Context, userID uuid.UUID, query string, limit, offset int) ([]ItemSearchHit, int64, error)
+}
+
+// ItemSearchHit is an item matching a full-text search, with where to find it.
+type ItemSearchHit struct {
+	models.Item
+	BoxStatus    string
+	LocationID   *uuid.UUID
+	LocationName *string
+	Rank         float64
 }
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+var (
+	ErrLocationFull  = errors.New("storage location is at capacity")
+	ErrLocationInUse = errors.New("storage location still holds boxes")
+)
+
+type LocationRepository interface {
+	Create(ctx context.Context, location *models.StorageLocation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.StorageLocation, error)
+	FindAll(ctx context.Context) ([]models.StorageLocation, error)
+	Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AssignBox(ctx context.Context, boxID uuid.UUID, locationID *uuid.UUID) error
+}
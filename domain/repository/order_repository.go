@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+// ErrOpenOrderExists is returned by Create when the box already has a requested
+// or in-progress order.
+var ErrOpenOrderExists = errors.New("box already has an open order")
+
+type OrderRepository interface {
+	// Create inserts the order. It returns ErrOpenOrderExists if the box
+	// already has an open one.
+	Create(ctx context.Context, order *models.StorageOrder) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.StorageOrder, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.StorageOrder, error)
+	FindByStatus(ctx context.Context, status string) ([]models.StorageOrder, error)
+	FindOpenByBoxID(ctx context.Context, boxID uuid.UUID) (*models.StorageOrder, error)
+	// UpdateStatus moves the order from status from to status to. It returns
+	// ErrVersionConflict if the order is no longer in status from.
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) error
+	// CompletePartialReturn takes the order's items out of its box, recording a
+	// "retrieved" quantity movement by actorID for each, and completes the order,
+	// all in one transaction. It returns ErrVersionConflict if the order's status
+	// changed since it was read, ErrItemsNotInBox if an item left the box
+	// and ErrNegativeQuantity if fewer items are left than requested.
+	CompletePartialReturn(ctx context.Context, order *models.StorageOrder, actorID uuid.UUID) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevocationRepository interface {
+	// RevokeToken denies the token with the given jti until expiresAt.
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// RevokeUserTokens denies the user's tokens issued before the given time.
+	// A cutoff earlier than the stored one is ignored.
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID, before time.Time) error
+	// UserTokenCutoff returns the time before which the user's tokens are
+	// revoked, or the zero time if there is none.
+	UserTokenCutoff(ctx context.Context, userID uuid.UUID) (time.Time, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
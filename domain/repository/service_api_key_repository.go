@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+type ServiceAPIKeyRepository interface {
+	Create(ctx context.Context, key *models.ServiceAPIKey) error
+	FindByHash(ctx context.Context, keyHash string) (*models.ServiceAPIKey, error)
+	FindAll(ctx context.Context) ([]models.ServiceAPIKey, error)
+	// Revoke marks the key as revoked. Revoking a revoked key keeps its
+	// original revocation time.
+	Revoke(ctx context.Context, id uuid.UUID) (*models.ServiceAPIKey, error)
+}
@@ -0,0 +1,7 @@
+package repository
+
+import "errors"
+
+// ErrVersionConflict is returned by conditional writes when the row's version no
+// longer matches the one the caller read or was given in If-Match.
+var ErrVersionConflict = errors.New("resource was modified by another request")
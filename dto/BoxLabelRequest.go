@@ -0,0 +1,8 @@
+package dto
+
+import "github.com/google/uuid"
+
+// BoxLabelBatchRequest is the body of POST /admin/boxes/labels
+type BoxLabelBatchRequest struct {
+	BoxIDs []uuid.UUID `json:"box_ids" binding:"required,min=1,max=100"`
+}
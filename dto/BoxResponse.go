@@ -1,11 +1,20 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // BoxResponse defines the structure returned when viewing a box
 type BoxResponse struct {
-	ID          uuid.UUID `json:"id"`
-	PackingMode string    `json:"packing_mode"`
-	Status      string    `json:"status"`
-	Items       []ItemDTO `json:"items"`
+	ID          uuid.UUID  `json:"id"`
+	Code        string     `json:"code"`
+	UserID      uuid.UUID  `json:"user_id"`
+	PackingMode string     `json:"packing_mode"`
+	Status      string     `json:"status"`
+	LocationID  *uuid.UUID `json:"location_id"`
+	Items       []ItemDTO  `json:"items"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Version     int        `json:"version"`
 }
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BoxStatusEventResponse is one entry of a box's status history
+type BoxStatusEventResponse struct {
+	FromStatus  string     `json:"from_status"`
+	ToStatus    string     `json:"to_status"`
+	ActorID     uuid.UUID  `json:"actor_id"`
+	AccountType string     `json:"account_type"`
+	Note        string     `json:"note,omitempty"`
+	LocationID  *uuid.UUID `json:"location_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
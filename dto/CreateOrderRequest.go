@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateOrderRequest struct {
+	BoxID         uuid.UUID `json:"box_id" binding:"required"`
+	Type          string    `json:"type" binding:"required,oneof=pickup return relocate"`
+	ScheduledDate time.Time `json:"scheduled_date" binding:"required"`
+	// Items turns a return into a partial return of these items. Only sort-packed
+	// boxes can be returned partially.
+	Items []OrderItemRequest `json:"items" binding:"omitempty,max=100,dive"`
+}
+
+// OrderItemRequest asks for quantity of an item back
+type OrderItemRequest struct {
+	ItemID   uuid.UUID `json:"item_id" binding:"required"`
+	Quantity int       `json:"quantity" binding:"required,min=1"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=requested in_progress completed cancelled"`
+}
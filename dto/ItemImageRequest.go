@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/google/uuid"
+
+// UpdateItemImageRequest changes an image's caption or makes it the primary image
+type UpdateItemImageRequest struct {
+	Caption *string `json:"caption,omitempty" binding:"omitempty,max=200"`
+	Primary *bool   `json:"primary,omitempty"`
+}
+
+// ReorderItemImagesRequest lists every image of the item in the new display order
+type ReorderItemImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids" binding:"required,min=1"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ItemImportRowError lists what is wrong with one row of a bulk import.
+// Rows are numbered from 1 in the order they were sent, not counting a CSV header.
+type ItemImportRowError struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+type ItemImportResponse struct {
+	IDs   []uuid.UUID `json:"ids"`
+	Count int         `json:"count"`
+}
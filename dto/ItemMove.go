@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MoveItemsRequest is the body of POST /boxes/:id/items/move
+type MoveItemsRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids" binding:"required,min=1,max=1000"`
+	// TargetBox is the ID or code of the box to move the items into
+	TargetBox string `json:"target_box" binding:"required"`
+}
+
+// MergeBoxesRequest is the body of POST /boxes/:id/merge
+type MergeBoxesRequest struct {
+	// SourceBox is the ID or code of the box to empty into this one
+	SourceBox string `json:"source_box" binding:"required"`
+}
+
+// SplitBoxRequest is the body of POST /boxes/:id/split
+type SplitBoxRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids" binding:"required,min=1,max=1000"`
+}
+
+// SplitBoxResponse identifies the box created by a split
+type SplitBoxResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Code string    `json:"code"`
+}
+
+// ItemMoveResponse is one entry of an item's history
+type ItemMoveResponse struct {
+	FromBoxID uuid.UUID `json:"from_box_id"`
+	ToBoxID   uuid.UUID `json:"to_box_id"`
+	Reason    string    `json:"reason"`
+	ActorID   uuid.UUID `json:"actor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuantityAdjustmentRequest is the body of POST /items/:id/quantity-movements.
+// Retrieved and damaged items are removed with a negative delta, added items
+// with a positive one; corrections may go either way.
+type QuantityAdjustmentRequest struct {
+	Delta  int    `json:"delta" binding:"required"`
+	Reason string `json:"reason" binding:"required,oneof=added retrieved damaged correction"`
+	Note   string `json:"note"`
+}
+
+// QuantityMovementResponse is one entry of an item's quantity ledger
+type QuantityMovementResponse struct {
+	Delta         int       `json:"delta"`
+	QuantityAfter int       `json:"quantity_after"`
+	Reason        string    `json:"reason"`
+	Note          string    `json:"note,omitempty"`
+	ActorID       uuid.UUID `json:"actor_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// QuantityAdjustmentResponse is the item's quantity after an adjustment
+type QuantityAdjustmentResponse struct {
+	Quantity int                      `json:"quantity"`
+	Version  int                      `json:"version"`
+	Movement QuantityMovementResponse `json:"movement"`
+}
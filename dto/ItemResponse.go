@@ -1,11 +1,30 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type ItemDTO struct {
 	ID          uuid.UUID `json:"id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Quantity    int       `json:"quantity"`
-	ImageURL    string    `json:"image_url"`
+	Version     int       `json:"version"`
+	// ImageURL and ThumbnailURL point at the primary image, kept for older clients
+	ImageURL     string         `json:"image_url"`
+	ThumbnailURL string         `json:"thumbnail_url,omitempty"`
+	Images       []ItemImageDTO `json:"images"`
+}
+
+// ItemImageDTO is one photo of an item. URLs are signed and expire.
+type ItemImageDTO struct {
+	ID           uuid.UUID `json:"id"`
+	Position     int       `json:"position"`
+	Caption      string    `json:"caption"`
+	Primary      bool      `json:"primary"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
 }
@@ -0,0 +1,25 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ItemSearchQuery holds the query parameters accepted by GET /items/search
+type ItemSearchQuery struct {
+	Q      string `form:"q" binding:"required,min=2,max=200"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+// ItemSearchHit is an item matching a search together with the box it is in
+type ItemSearchHit struct {
+	ItemDTO
+	BoxID        uuid.UUID  `json:"box_id"`
+	BoxStatus    string     `json:"box_status"`
+	LocationID   *uuid.UUID `json:"location_id"`
+	LocationName *string    `json:"location_name"`
+	Rank         float64    `json:"rank"`
+}
+
+type ItemSearchResponse struct {
+	Results []ItemSearchHit `json:"results"`
+	Total   int64           `json:"total"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// ListBoxesQuery holds the query parameters accepted by GET /boxes
+type ListBoxesQuery struct {
+	Status      string `form:"status" binding:"omitempty,oneof=in_transit pending_pack pending_pickup stored returned disposed"`
+	PackingMode string `form:"packing_mode" binding:"omitempty,oneof=self sort"`
+	LocationID  string `form:"location_id" binding:"omitempty,uuid"`
+	Sort        string `form:"sort" binding:"omitempty,oneof=created_at -created_at"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string `form:"cursor"`
+	Include     string `form:"include" binding:"omitempty,oneof=items"`
+}
+
+// AdminListBoxesQuery holds the query parameters accepted by GET /admin/boxes.
+// The created_at range includes CreatedFrom and excludes CreatedTo.
+type AdminListBoxesQuery struct {
+	ListBoxesQuery
+	UserID      string    `form:"user_id" binding:"omitempty,uuid"`
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// BoxListResponse is one page of boxes. NextCursor is empty on the last page.
+type BoxListResponse struct {
+	Boxes      []BoxResponse `json:"boxes"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Total      int64         `json:"total"`
+}
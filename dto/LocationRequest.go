@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateLocationRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Address  string `json:"address" binding:"required"`
+	Capacity int    `json:"capacity" binding:"required,min=1"`
+}
+
+type UpdateLocationRequest struct {
+	Name     *string `json:"name,omitempty" binding:"omitempty,max=100"`
+	Address  *string `json:"address,omitempty"`
+	Capacity *int    `json:"capacity,omitempty" binding:"omitempty,min=1"`
+}
+
+type PlaceBoxRequest struct {
+	LocationID uuid.UUID `json:"location_id" binding:"required"`
+}
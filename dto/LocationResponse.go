@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/google/uuid"
+
+// LocationResponse defines the structure returned when viewing a storage location
+type LocationResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Address     string    `json:"address"`
+	Capacity    int       `json:"capacity"`
+	CurrentLoad int       `json:"current_load"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderResponse defines the structure returned when viewing a storage order
+type OrderResponse struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"user_id"`
+	BoxID         uuid.UUID `json:"box_id"`
+	Type          string    `json:"type"`
+	ScheduledDate time.Time `json:"scheduled_date"`
+	Status        string    `json:"status"`
+	// Items is only set for partial returns
+	Items     []OrderItemResponse `json:"items,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// OrderItemResponse is an item and quantity requested by a partial return
+type OrderItemResponse struct {
+	ItemID   uuid.UUID `json:"item_id"`
+	Quantity int       `json:"quantity"`
+}
+
+// PickListResponse tells an employee where a return order's box is and what to
+// take out of it. WholeBox returns list every item of the box.
+type PickListResponse struct {
+	OrderID       uuid.UUID      `json:"order_id"`
+	Status        string         `json:"status"`
+	ScheduledDate time.Time      `json:"scheduled_date"`
+	BoxID         uuid.UUID      `json:"box_id"`
+	BoxCode       string         `json:"box_code"`
+	Location      *string        `json:"location"`
+	WholeBox      bool           `json:"whole_box"`
+	Items         []PickListItem `json:"items"`
+}
+
+// PickListItem is one item to pick, with how many of it are in the box
+type PickListItem struct {
+	ItemID      uuid.UUID `json:"item_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Quantity    int       `json:"quantity"`
+	InBox       int       `json:"in_box"`
+}
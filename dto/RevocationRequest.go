@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokeTokenRequest is the body of POST /internal/revocations/tokens
+type RevokeTokenRequest struct {
+	JTI string `json:"jti" binding:"required,max=255"`
+	// ExpiresAt is the token's exp; the revocation is dropped once it has passed
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+}
+
+// RevokeUserTokensRequest is the body of POST /internal/revocations/users
+type RevokeUserTokensRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	// IssuedBefore revokes the user's tokens issued before it, defaulting to now
+	IssuedBefore *time.Time `json:"issued_before"`
+}
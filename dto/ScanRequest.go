@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ScanBoxRequest is the body of POST /boxes/scan
+type ScanBoxRequest struct {
+	// BoxCode is the scanned box code or the box ID from the label's QR code
+	BoxCode    string    `json:"box_code" binding:"required"`
+	LocationID uuid.UUID `json:"location_id" binding:"required"`
+	Note       string    `json:"note"`
+}
+
+// ScanBoxResponse describes the status change a scan applied
+type ScanBoxResponse struct {
+	BoxID      uuid.UUID  `json:"box_id"`
+	Code       string     `json:"code"`
+	FromStatus string     `json:"from_status"`
+	ToStatus   string     `json:"to_status"`
+	LocationID *uuid.UUID `json:"location_id"`
+	Version    int        `json:"version"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateServiceAPIKeyRequest is the body of POST /admin/api-keys
+type CreateServiceAPIKeyRequest struct {
+	// Name identifies the calling service in logs, e.g. "billing"
+	Name string `json:"name" binding:"required,max=100"`
+	// Scopes are the permissions the key grants, e.g. "order:read_any"
+	Scopes    []string   `json:"scopes" binding:"required,min=1,max=50,dive,required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ServiceAPIKeyResponse describes a service API key without the key itself
+type ServiceAPIKeyResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	CreatedBy uuid.UUID  `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// CreatedServiceAPIKeyResponse is returned once when a key is issued. Key is
+// not stored and cannot be retrieved again.
+type CreatedServiceAPIKeyResponse struct {
+	ServiceAPIKeyResponse
+	Key string `json:"key"`
+}
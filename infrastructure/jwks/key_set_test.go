@@ -0,0 +1,148 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sandroJayas/storage-service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func encode(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": encode(key.N), "e": encode(big.NewInt(int64(key.E)))}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": encode(key.X), "y": encode(key.Y)}
+}
+
+func writeJWKS(t *testing.T, path string, keys ...map[string]string) {
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestParseKeys(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	data, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		rsaJWK("rsa-1", &rsaKey.PublicKey),
+		ecJWK("ec-1", &ecKey.PublicKey),
+		{"kty": "oct", "kid": "shared", "k": "c2VjcmV0"},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "AQAB", "e": "AQAB"},
+	}})
+
+	keys, err := ParseKeys(data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, keys, 2)
+	assert.True(t, rsaKey.PublicKey.Equal(keys["rsa-1"]))
+	assert.True(t, ecKey.PublicKey.Equal(keys["ec-1"]))
+
+	_, err = ParseKeys([]byte(`{"keys":[{"kty":"EC","kid":"bad","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	assert.Error(t, err)
+	_, err = ParseKeys([]byte(`{"keys":[]}`))
+	assert.Error(t, err)
+}
+
+func TestKeySetPicksUpRotatedKeys(t *testing.T) {
+	utils.InitLogger()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	writeJWKS(t, path, rsaJWK("old", &oldKey.PublicKey))
+
+	keys, err := NewKeySet(context.Background(), path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = keys.PublicKey(context.Background(), "old")
+	assert.NoError(t, err)
+
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	writeJWKS(t, path, rsaJWK("old", &oldKey.PublicKey), ecJWK("new", &newKey.PublicKey))
+
+	// Just refreshed, so an unknown key ID does not reload yet
+	_, err = keys.PublicKey(context.Background(), "new")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	keys.mu.Lock()
+	keys.attempted = time.Now().Add(-minRefreshInterval)
+	keys.mu.Unlock()
+	key, err := keys.PublicKey(context.Background(), "new")
+	assert.NoError(t, err)
+	assert.True(t, newKey.PublicKey.Equal(key))
+
+	// A broken document keeps the keys loaded before
+	assert.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+	assert.Error(t, keys.Refresh(context.Background()))
+	_, err = keys.PublicKey(context.Background(), "old")
+	assert.NoError(t, err)
+}
+
+func TestKeySetReloadsOncePerInterval(t *testing.T) {
+	utils.InitLogger()
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	var hits atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{rsaJWK("a", &key.PublicKey)}})
+	}))
+	defer server.Close()
+
+	keys, err := NewKeySet(context.Background(), server.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Concurrent misses on a stale set reload the document once
+	keys.mu.Lock()
+	keys.attempted = time.Now().Add(-minRefreshInterval)
+	keys.mu.Unlock()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			keys.PublicKey(context.Background(), "unknown")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), hits.Load())
+
+	// A failed reload counts too, so a down endpoint is not hit on every miss
+	failing.Store(true)
+	keys.mu.Lock()
+	keys.attempted = time.Now().Add(-minRefreshInterval)
+	keys.mu.Unlock()
+	for i := 0; i < 3; i++ {
+		_, err = keys.PublicKey(context.Background(), "unknown")
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	}
+	assert.Equal(t, int32(3), hits.Load())
+	_, err = keys.PublicKey(context.Background(), "a")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,39 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sandroJayas/storage-service/domain/event"
+)
+
+// MemoryPublisher keeps published events in memory. It is meant for tests.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []event.Event
+	// FailNext makes the next n Publish calls return Err, to exercise retries.
+	FailNext int
+	Err      error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, e event.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.FailNext > 0 {
+		p.FailNext--
+		return p.Err
+	}
+	p.events = append(p.events, e)
+	return nil
+}
+
+// Events returns a copy of everything published so far.
+func (p *MemoryPublisher) Events() []event.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]event.Event(nil), p.events...)
+}
@@ -0,0 +1,214 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sandroJayas/storage-service/domain/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis is a local stand-in for a Redis server that understands the
+// commands RedisLimiter sends.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	counters map[string]int64
+	expiries map[string]int64
+}
+
+func startFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeRedis{listener: listener, password: password, counters: map[string]int64{}, expiries: map[string]int64{}}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := s.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		args := reply.([]interface{})
+		cmd := strings.ToUpper(args[0].(string))
+
+		s.mu.Lock()
+		var out string
+		switch {
+		case cmd == "AUTH":
+			authed = args[1].(string) == s.password
+			out = "+OK\r\n"
+			if !authed {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required.\r\n"
+		case cmd == "INCR":
+			s.counters[args[1].(string)]++
+			out = ":" + strconv.FormatInt(s.counters[args[1].(string)], 10) + "\r\n"
+		case cmd == "PEXPIREAT":
+			at, _ := strconv.ParseInt(args[2].(string), 10, 64)
+			s.expiries[args[1].(string)] = at
+			out = ":1\r\n"
+		default:
+			out = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 3, Window: time.Minute}
+	now := time.Date(2025, 6, 1, 12, 0, 20, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	newLimiters := map[string]func(t *testing.T) ratelimit.Limiter{
+		"memory": func(t *testing.T) ratelimit.Limiter {
+			l := NewMemoryLimiter()
+			l.now = clock
+			return l
+		},
+		"redis": func(t *testing.T) ratelimit.Limiter {
+			server := startFakeRedis(t, "secret")
+			l := NewRedisLimiter(server.listener.Addr().String(), "secret", 0)
+			l.now = clock
+			t.Cleanup(func() { l.Close() })
+			return l
+		},
+	}
+
+	for name, newLimiter := range newLimiters {
+		t.Run(name, func(t *testing.T) {
+			now = time.Date(2025, 6, 1, 12, 0, 20, 0, time.UTC)
+			l := newLimiter(t)
+
+			for i := 1; i <= 3; i++ {
+				res, err := l.Allow(ctx, "user:a", limit)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.True(t, res.Allowed)
+				assert.Equal(t, 3-i, res.Remaining)
+				assert.Equal(t, 40*time.Second, res.ResetAfter)
+			}
+
+			res, err := l.Allow(ctx, "user:a", limit)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+
+			res, err = l.Allow(ctx, "user:b", limit)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.True(t, res.Allowed, "other keys have their own count")
+
+			now = now.Add(40 * time.Second)
+			res, err = l.Allow(ctx, "user:a", limit)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.True(t, res.Allowed, "the count restarts with the next window")
+			assert.Equal(t, 2, res.Remaining)
+		})
+	}
+}
+
+func TestRedisLimiterSharesCounts(t *testing.T) {
+	ctx := context.Background()
+	server := startFakeRedis(t, "")
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+
+	now := time.Now()
+	replicaA := NewRedisLimiter(server.listener.Addr().String(), "", 0)
+	replicaB := NewRedisLimiter(server.listener.Addr().String(), "", 0)
+	replicaA.now = func() time.Time { return now }
+	replicaB.now = func() time.Time { return now }
+	defer replicaA.Close()
+	defer replicaB.Close()
+
+	_, err := replicaA.Allow(ctx, "user:a", limit)
+	assert.NoError(t, err)
+	_, err = replicaB.Allow(ctx, "user:a", limit)
+	assert.NoError(t, err)
+	res, err := replicaA.Allow(ctx, "user:a", limit)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, res.Allowed)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for key, at := range server.expiries {
+		assert.True(t, strings.HasPrefix(key, "user:a:"))
+		assert.Greater(t, at, now.UnixMilli())
+	}
+}
+
+func TestRedisLimiterErrors(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+
+	server := startFakeRedis(t, "secret")
+	l := NewRedisLimiter(server.listener.Addr().String(), "wrong", 0)
+	_, err := l.Allow(ctx, "user:a", limit)
+	assert.ErrorContains(t, err, "WRONGPASS")
+
+	server.listener.Close()
+	l = NewRedisLimiter(server.listener.Addr().String(), "", 0)
+	_, err = l.Allow(ctx, "user:a", limit)
+	assert.Error(t, err)
+}
+
+func TestRedisLimiterBoundsOpenConnections(t *testing.T) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+	server := startFakeRedis(t, "")
+
+	l := NewRedisLimiter(server.listener.Addr().String(), "", 0)
+	l.open = make(chan struct{}, 1)
+	l.timeout = 50 * time.Millisecond
+	defer l.Close()
+
+	held, err := l.conn(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = l.Allow(ctx, "user:a", limit)
+	assert.ErrorIs(t, err, errPoolExhausted)
+
+	l.release(held)
+	_, err = l.Allow(ctx, "user:a", limit)
+	assert.NoError(t, err)
+}
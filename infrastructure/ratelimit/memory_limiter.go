@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sandroJayas/storage-service/domain/ratelimit"
+)
+
+// MemoryLimiter counts requests in process memory. Counts are lost on restart
+// and not shared between replicas; use RedisLimiter for that.
+type MemoryLimiter struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]memoryWindow
+	lastSweep time.Time
+}
+
+type memoryWindow struct {
+	start time.Time
+	end   time.Time
+	count int
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{now: time.Now, windows: make(map[string]memoryWindow)}
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	now := l.now()
+	start, resetAfter := ratelimit.Window(now, limit.Window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	w := l.windows[key]
+	if !w.start.Equal(start) {
+		w = memoryWindow{start: start, end: start.Add(limit.Window)}
+	}
+	w.count++
+	l.windows[key] = w
+
+	return result(w.count, limit, resetAfter), nil
+}
+
+// sweep drops the windows that have ended, at most once a minute.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, w := range l.windows {
+		if !now.Before(w.end) {
+			delete(l.windows, key)
+		}
+	}
+}
+
+// result builds the Result of the count-th request of a window.
+func result(count int, limit ratelimit.Limit, resetAfter time.Duration) ratelimit.Result {
+	remaining := limit.Requests - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return ratelimit.Result{
+		Allowed:    count <= limit.Requests,
+		Limit:      limit.Requests,
+		Remaining:  remaining,
+		ResetAfter: resetAfter,
+	}
+}
@@ -0,0 +1,254 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/sandroJayas/storage-service/domain/ratelimit"
+)
+
+const (
+	// maxIdleConns bounds the connections kept open between requests.
+	maxIdleConns = 16
+	// maxOpenConns bounds all connections, idle or in use. A request that finds
+	// none free waits up to the limiter's timeout for one.
+	maxOpenConns = 64
+)
+
+// RedisLimiter counts requests in a Redis-protocol server, so every replica
+// pointed at the same server shares the counts. Each request costs one round
+// trip: INCR of the window's counter pipelined with a PEXPIREAT at its end.
+type RedisLimiter struct {
+	addr     string
+	password string
+	db       int
+	timeout  time.Duration
+	now      func() time.Time
+
+	idle chan *redisConn
+	// open holds a token for every open connection
+	open chan struct{}
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+func NewRedisLimiter(addr, password string, db int) *RedisLimiter {
+	return &RedisLimiter{
+		addr:     addr,
+		password: password,
+		db:       db,
+		timeout:  time.Second,
+		now:      time.Now,
+		idle:     make(chan *redisConn, maxIdleConns),
+		open:     make(chan struct{}, maxOpenConns),
+	}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	now := l.now()
+	start, resetAfter := ratelimit.Window(now, limit.Window)
+	windowKey := key + ":" + strconv.FormatInt(start.UnixMilli(), 10)
+	// Keep the counter a little past the window so clock skew between replicas
+	// does not drop it early
+	expireAt := start.Add(limit.Window + time.Second).UnixMilli()
+
+	replies, err := l.pipeline(ctx,
+		[]string{"INCR", windowKey},
+		[]string{"PEXPIREAT", windowKey, strconv.FormatInt(expireAt, 10)},
+	)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	count, ok := replies[0].(int64)
+	if !ok {
+		return ratelimit.Result{}, fmt.Errorf("unexpected INCR reply %v", replies[0])
+	}
+	return result(int(count), limit, resetAfter), nil
+}
+
+// Close closes the idle connections.
+func (l *RedisLimiter) Close() error {
+	for {
+		select {
+		case conn := <-l.idle:
+			l.close(conn)
+		default:
+			return nil
+		}
+	}
+}
+
+// pipeline sends the commands in one write and reads their replies. A reply
+// that is a Redis error is returned as the error.
+func (l *RedisLimiter) pipeline(ctx context.Context, cmds ...[]string) ([]interface{}, error) {
+	conn, err := l.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(l.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	replies, err := conn.do(cmds...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// The connection may hold half a reply, so it cannot be reused
+		l.close(conn)
+		return nil, err
+	}
+	l.release(conn)
+	return replies, err
+}
+
+// conn returns an idle connection or dials a new one. With maxOpenConns open
+// it waits for one to be released, so a slow server cannot make every request
+// open another connection.
+func (l *RedisLimiter) conn(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-l.idle:
+		return conn, nil
+	default:
+	}
+
+	wait := time.NewTimer(l.timeout)
+	defer wait.Stop()
+	select {
+	case conn := <-l.idle:
+		return conn, nil
+	case l.open <- struct{}{}:
+	case <-wait.C:
+		return nil, errPoolExhausted
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	dialer := net.Dialer{Timeout: l.timeout}
+	c, err := dialer.DialContext(ctx, "tcp", l.addr)
+	if err != nil {
+		<-l.open
+		return nil, err
+	}
+	conn := &redisConn{Conn: c, r: bufio.NewReader(c), w: bufio.NewWriter(c)}
+
+	var setup [][]string
+	if l.password != "" {
+		setup = append(setup, []string{"AUTH", l.password})
+	}
+	if l.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(l.db)})
+	}
+	if len(setup) > 0 {
+		conn.SetDeadline(time.Now().Add(l.timeout))
+		if _, err := conn.do(setup...); err != nil {
+			l.close(conn)
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (l *RedisLimiter) release(conn *redisConn) {
+	select {
+	case l.idle <- conn:
+	default:
+		l.close(conn)
+	}
+}
+
+func (l *RedisLimiter) close(conn *redisConn) {
+	conn.Close()
+	<-l.open
+}
+
+var errPoolExhausted = errors.New("redis: all connections are in use")
+
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func (c *redisConn) do(cmds ...[]string) ([]interface{}, error) {
+	for _, cmd := range cmds {
+		fmt.Fprintf(c.w, "*%d\r\n", len(cmd))
+		for _, arg := range cmd {
+			fmt.Fprintf(c.w, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies := make([]interface{}, len(cmds))
+	var firstErr error
+	for i := range cmds {
+		reply, err := readReply(c.r)
+		var redisErr redisError
+		if err != nil && !errors.As(err, &redisErr) {
+			return nil, err
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		replies[i] = reply
+	}
+	return replies, firstErr
+}
+
+// readReply reads one RESP reply: simple strings and bulk strings as string,
+// integers as int64, arrays as []interface{} and nil bulk strings as nil.
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	payload := line[1 : len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return payload, nil
+	case '-':
+		return nil, redisError(payload)
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(payload)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", line[0])
+	}
+}
@@ -2,13 +2,19 @@ package repository
 
 import (
 	"context"
-	"github.com/sandroJayas/storage-service/dto"
+	"errors"
+	"github.com/sandroJayas/storage-service/domain/repository"
 	"github.com/sandroJayas/storage-service/models"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// pgUniqueViolation is the SQLSTATE postgres reports for unique index conflicts.
+const pgUniqueViolation = "23505"
+
 type GormBoxRepository struct {
 	db *gorm.DB
 }
@@ -17,14 +23,39 @@ func NewGormBoxRepository(db *gorm.DB) *GormBoxRepository {
 	return &GormBoxRepository{db}
 }
 
+// Create inserts the box and its items. It returns repository.ErrBoxCodeTaken
+// when box.Code is already in use.
 func (r *GormBoxRepository) Create(ctx context.Context, box *models.Box) error {
-	return r.db.WithContext(ctx).Create(box).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(box).Error; err != nil {
+			if isBoxCodeConflict(err) {
+				return repository.ErrBoxCodeTaken
+			}
+			return err
+		}
+		if err := recordInitialQuantities(tx, box.Items, box.UserID); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, "box", box.ID, "box.created", map[string]interface{}{
+			"box_id":       box.ID,
+			"user_id":      box.UserID,
+			"packing_mode": box.PackingMode,
+			"status":       box.Status,
+		})
+	})
+}
+
+// isBoxCodeConflict reports whether err is a violation of the unique box code.
+func isBoxCodeConflict(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == "idx_boxes_code"
 }
 
 func (r *GormBoxRepository) FindByID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Box, error) {
 	var box models.Box
 	err := r.db.WithContext(ctx).
 		Preload("Items").
+		Preload("Items.Images", orderedImages).
 		Where("id = ? AND user_id = ?", id, userID).
 		First(&box).Error
 	if err != nil {
@@ -33,42 +64,245 @@ func (r *GormBoxRepository) FindByID(ctx context.Context, id uuid.UUID, userID u
 	return &box, nil
 }
 
-func (r *GormBoxRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.Box, error) {
+// FindAnyByID looks a box up regardless of its owner, for employee workflows.
+// It also loads the box's storage location.
+func (r *GormBoxRepository) FindAnyByID(ctx context.Context, id uuid.UUID) (*models.Box, error) {
+	var box models.Box
+	err := r.db.WithContext(ctx).
+		Preload("Location").
+		Preload("Items").
+		Preload("Items.Images", orderedImages).
+		Where("id = ?", id).
+		First(&box).Error
+	if err != nil {
+		return nil, err
+	}
+	return &box, nil
+}
+
+func (r *GormBoxRepository) FindIDByCode(ctx context.Context, code string) (uuid.UUID, error) {
+	var box models.Box
+	err := r.db.WithContext(ctx).Select("id").Where("code = ?", code).First(&box).Error
+	return box.ID, err
+}
+
+func (r *GormBoxRepository) FindAnyByIDs(ctx context.Context, ids []uuid.UUID) ([]models.Box, error) {
 	var boxes []models.Box
-	err := r.db.WithContext(ctx).Preload("Items").Where("user_id = ?", userID).Find(&boxes).Error
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&boxes).Error
 	return boxes, err
 }
 
-func (r *GormBoxRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
-	return r.db.WithContext(ctx).Model(&models.Box{}).Where("id = ?", id).Update("status", status).Error
+func (r *GormBoxRepository) FindPage(ctx context.Context, filter repository.BoxFilter, page repository.BoxPage) ([]models.Box, error) {
+	query := applyBoxFilter(r.db.WithContext(ctx), filter)
+	if page.IncludeItems {
+		query = query.Preload("Items").Preload("Items.Images", orderedImages)
+	}
+
+	if page.AfterID != uuid.Nil {
+		op := "<"
+		if page.Ascending {
+			op = ">"
+		}
+		query = query.Where("(created_at, id) "+op+" (?, ?)", page.AfterCreatedAt, page.AfterID)
+	}
+
+	if page.Ascending {
+		query = query.Order("created_at ASC, id ASC")
+	} else {
+		query = query.Order("created_at DESC, id DESC")
+	}
+
+	var boxes []models.Box
+	err := query.Limit(page.Limit).Find(&boxes).Error
+	return boxes, err
 }
 
-func (r *GormBoxRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Box{}).Error
+func (r *GormBoxRepository) Count(ctx context.Context, filter repository.BoxFilter) (int64, error) {
+	var count int64
+	err := applyBoxFilter(r.db.WithContext(ctx).Model(&models.Box{}), filter).Count(&count).Error
+	return count, err
 }
 
-func (r *GormBoxRepository) UpdateItem(ctx context.Context, boxID, itemID uuid.UUID, req dto.UpdateItemRequest) error {
-	var updates = map[string]interface{}{}
+func applyBoxFilter(query *gorm.DB, filter repository.BoxFilter) *gorm.DB {
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.PackingMode != "" {
+		query = query.Where("packing_mode = ?", filter.PackingMode)
+	}
+	if filter.LocationID != nil {
+		query = query.Where("location_id = ?", *filter.LocationID)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom.UTC())
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo.UTC())
+	}
+	return query
+}
+
+// UpdateStatus moves the box from event.FromStatus to event.ToStatus and records
+// the event in the same transaction. It returns repository.ErrVersionConflict
+// when the box is no longer at the given version.
+func (r *GormBoxRepository) UpdateStatus(ctx context.Context, event *models.BoxStatusEvent, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Box{}).
+			Where("id = ? AND status = ? AND version = ?", event.BoxID, event.FromStatus, version).
+			Updates(map[string]interface{}{
+				"status":  event.ToStatus,
+				"version": gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrVersionConflict
+		}
+		return recordStatusEvent(tx, event)
+	})
+}
+
+// UpdateStatusAndLocation works like UpdateStatus and also moves the box to
+// locationID, or off its location when locationID is nil, in the same
+// transaction. Location loads are adjusted as in LocationRepository.AssignBox.
+func (r *GormBoxRepository) UpdateStatusAndLocation(ctx context.Context, event *models.BoxStatusEvent, version int, locationID *uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var box models.Box
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", event.BoxID).First(&box).Error
+		if err != nil {
+			return err
+		}
+		if box.Status != event.FromStatus || box.Version != version {
+			return repository.ErrVersionConflict
+		}
+		if event.LocationID != nil {
+			if err := tx.Where("id = ?", *event.LocationID).First(&models.StorageLocation{}).Error; err != nil {
+				return err
+			}
+		}
+		if !sameLocation(box.LocationID, locationID) {
+			if err := moveBoxLoad(tx, box.LocationID, locationID); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&box).Updates(map[string]interface{}{
+			"status":      event.ToStatus,
+			"location_id": locationID,
+			"version":     gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return recordStatusEvent(tx, event)
+	})
+}
 
-	if req.Name != nil {
-		updates["name"] = *req.Name
+// recordStatusEvent adds the event to the box's history and the change feed.
+func recordStatusEvent(tx *gorm.DB, event *models.BoxStatusEvent) error {
+	if err := tx.Create(event).Error; err != nil {
+		return err
 	}
-	if req.Description != nil {
-		updates["description"] = *req.Description
+
+	userID, err := boxOwner(tx, event.BoxID)
+	if err != nil {
+		return err
 	}
-	if req.Quantity != nil {
-		updates["quantity"] = *req.Quantity
+	payload := map[string]interface{}{
+		"box_id":       event.BoxID,
+		"user_id":      userID,
+		"from_status":  event.FromStatus,
+		"to_status":    event.ToStatus,
+		"actor_id":     event.ActorID,
+		"account_type": event.AccountType,
 	}
-	if req.ImageURL != nil {
-		updates["image_url"] = *req.ImageURL
+	if event.LocationID != nil {
+		payload["location_id"] = event.LocationID
 	}
+	return enqueueEvent(tx, "box", event.BoxID, "box.status_changed", payload)
+}
+
+func (r *GormBoxRepository) FindStatusEvents(ctx context.Context, id uuid.UUID) ([]models.BoxStatusEvent, error) {
+	var events []models.BoxStatusEvent
+	err := r.db.WithContext(ctx).
+		Where("box_id = ?", id).
+		Order("created_at ASC").
+		Find(&events).Error
+	return events, err
+}
 
-	if len(updates) == 0 {
-		return nil // Nothing to update
+// SoftDelete deletes the box and frees its place at its location. With a
+// version it only deletes the box at that version and returns
+// repository.ErrVersionConflict otherwise.
+func (r *GormBoxRepository) SoftDelete(ctx context.Context, id uuid.UUID, version *int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var box models.Box
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&box).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if version != nil {
+				return repository.ErrVersionConflict
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if version != nil && box.Version != *version {
+			return repository.ErrVersionConflict
+		}
+
+		// A deleted box gives up its place at the location
+		if box.LocationID != nil {
+			if err := moveBoxLoad(tx, box.LocationID, nil); err != nil {
+				return err
+			}
+		}
+		err = tx.Model(&box).Updates(map[string]interface{}{
+			"location_id": nil,
+			"version":     gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&box).Error; err != nil {
+			return err
+		}
+		return enqueueEvent(tx, "box", box.ID, "box.deleted", map[string]interface{}{
+			"box_id":  box.ID,
+			"user_id": box.UserID,
+		})
+	})
+}
+
+func (r *GormBoxRepository) StreamInventory(ctx context.Context, userID uuid.UUID, fn func(repository.InventoryRow) error) error {
+	db := r.db.WithContext(ctx)
+	rows, err := db.
+		Table("boxes").
+		Select("boxes.id AS box_id, boxes.code AS box_code, boxes.status AS box_status, boxes.packing_mode, boxes.created_at AS box_created_at, "+
+			"storage_locations.name AS location_name, items.id AS item_id, items.name AS item_name, "+
+			"items.description AS item_description, items.quantity AS item_quantity").
+		Joins("LEFT JOIN storage_locations ON storage_locations.id = boxes.location_id").
+		Joins("LEFT JOIN items ON items.box_id = boxes.id AND items.deleted_at IS NULL").
+		Where("boxes.user_id = ? AND boxes.deleted_at IS NULL", userID).
+		Order("boxes.created_at ASC, boxes.id ASC, items.created_at ASC, items.id ASC").
+		Rows()
+	if err != nil {
+		return err
 	}
+	defer rows.Close()
 
-	return r.db.WithContext(ctx).
-		Model(&models.Item{}).
-		Where("id = ? AND box_id = ?", itemID, boxID).
-		Updates(updates).Error
+	for rows.Next() {
+		var row repository.InventoryRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
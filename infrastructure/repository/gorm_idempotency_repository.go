@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormIdempotencyRepository(db *gorm.DB) *GormIdempotencyRepository {
+	return &GormIdempotencyRepository{db}
+}
+
+func (r *GormIdempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	var existing *models.IdempotencyKey
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+		if result.Error != nil || result.RowsAffected == 1 {
+			return result.Error
+		}
+
+		var current models.IdempotencyKey
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND key = ?", record.UserID, record.Key).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if current.ExpiresAt.After(time.Now()) {
+			existing = &current
+			return nil
+		}
+
+		// The old record has expired, so the key is free to be reused
+		return tx.Save(record).Error
+	})
+	return existing, err
+}
+
+func (r *GormIdempotencyRepository) Complete(ctx context.Context, userID uuid.UUID, key string, status int, contentType string, body []byte) error {
+	return r.db.WithContext(ctx).
+		Model(&models.IdempotencyKey{}).
+		Where("user_id = ? AND key = ?", userID, key).
+		Updates(map[string]interface{}{
+			"response_status": status,
+			"content_type":    contentType,
+			"response_body":   body,
+		}).Error
+}
+
+func (r *GormIdempotencyRepository) Release(ctx context.Context, userID uuid.UUID, key string) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND key = ? AND response_status IS NULL", userID, key).
+		Delete(&models.IdempotencyKey{}).Error
+}
+
+func (r *GormIdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", now).
+		Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
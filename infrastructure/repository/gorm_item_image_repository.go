@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormItemImageRepository struct {
+	db *gorm.DB
+}
+
+func NewGormItemImageRepository(db *gorm.DB) *GormItemImageRepository {
+	return &GormItemImageRepository{db}
+}
+
+func (r *GormItemImageRepository) Create(ctx context.Context, image *models.ItemImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the item row so concurrent uploads get distinct positions
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			First(&models.Item{}, "id = ?", image.ItemID).Error; err != nil {
+			return err
+		}
+
+		var stats struct {
+			Count       int64
+			MaxPosition int
+		}
+		if err := tx.Model(&models.ItemImage{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(position), -1) AS max_position").
+			Where("item_id = ?", image.ItemID).
+			Scan(&stats).Error; err != nil {
+			return err
+		}
+
+		image.Position = stats.MaxPosition + 1
+		if stats.Count == 0 {
+			image.IsPrimary = true
+		} else if image.IsPrimary {
+			if err := clearPrimaryImage(tx, image.ItemID); err != nil {
+				return err
+			}
+		}
+		return tx.Create(image).Error
+	})
+}
+
+func (r *GormItemImageRepository) ListByItemID(ctx context.Context, itemID uuid.UUID) ([]models.ItemImage, error) {
+	var images []models.ItemImage
+	err := r.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("position ASC").
+		Find(&images).Error
+	return images, err
+}
+
+func (r *GormItemImageRepository) FindByID(ctx context.Context, itemID, imageID uuid.UUID) (*models.ItemImage, error) {
+	var image models.ItemImage
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND item_id = ?", imageID, itemID).
+		First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *GormItemImageRepository) Update(ctx context.Context, image *models.ItemImage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if image.IsPrimary {
+			if err := clearPrimaryImage(tx, image.ItemID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(image).Updates(map[string]interface{}{"caption": image.Caption, "is_primary": image.IsPrimary}).Error
+	})
+}
+
+func (r *GormItemImageRepository) Reorder(ctx context.Context, itemID uuid.UUID, imageIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []uuid.UUID
+		if err := tx.Model(&models.ItemImage{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("item_id = ?", itemID).
+			Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if !sameImageSet(existing, imageIDs) {
+			return repository.ErrImageOrderMismatch
+		}
+
+		for position, id := range imageIDs {
+			if err := tx.Model(&models.ItemImage{}).
+				Where("id = ?", id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GormItemImageRepository) Delete(ctx context.Context, itemID, imageID uuid.UUID) (*models.ItemImage, error) {
+	var image models.ItemImage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Returning{}).
+			Where("id = ? AND item_id = ?", imageID, itemID).
+			Delete(&image).Error; err != nil {
+			return err
+		}
+		if image.ID == uuid.Nil {
+			return gorm.ErrRecordNotFound
+		}
+		if !image.IsPrimary {
+			return nil
+		}
+
+		// The next image in order takes over as primary
+		var next models.ItemImage
+		err := tx.Where("item_id = ?", itemID).Order("position ASC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_primary", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func clearPrimaryImage(tx *gorm.DB, itemID uuid.UUID) error {
+	return tx.Model(&models.ItemImage{}).
+		Where("item_id = ? AND is_primary", itemID).
+		Update("is_primary", false).Error
+}
+
+func sameImageSet(existing, requested []uuid.UUID) bool {
+	if len(existing) != len(requested) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		seen[id] = true
+	}
+	for _, id := range requested {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
+// orderedImages preloads an item's images in display order.
+func orderedImages(db *gorm.DB) *gorm.DB {
+	return db.Order("item_images.position ASC")
+}
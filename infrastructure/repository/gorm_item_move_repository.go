@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormItemMoveRepository struct {
+	db *gorm.DB
+}
+
+func NewGormItemMoveRepository(db *gorm.DB) *GormItemMoveRepository {
+	return &GormItemMoveRepository{db}
+}
+
+func (r *GormItemMoveRepository) MoveItems(ctx context.Context, fromBoxID, toBoxID uuid.UUID, itemIDs []uuid.UUID, actorID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockBoxes(tx, fromBoxID, toBoxID); err != nil {
+			return err
+		}
+		return moveItems(tx, fromBoxID, toBoxID, itemIDs, "move", actorID)
+	})
+}
+
+func (r *GormItemMoveRepository) MergeBoxes(ctx context.Context, targetBoxID, sourceBoxID uuid.UUID, actorID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockBoxes(tx, targetBoxID, sourceBoxID); err != nil {
+			return err
+		}
+
+		var itemIDs []uuid.UUID
+		err := tx.Model(&models.Item{}).Where("box_id = ?", sourceBoxID).Pluck("id", &itemIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(itemIDs) > 0 {
+			if err := moveItems(tx, sourceBoxID, targetBoxID, itemIDs, "merge", actorID); err != nil {
+				return err
+			}
+		}
+
+		var source models.Box
+		if err := tx.Where("id = ?", sourceBoxID).First(&source).Error; err != nil {
+			return err
+		}
+		if err := moveBoxLoad(tx, source.LocationID, nil); err != nil {
+			return err
+		}
+		err = tx.Model(&source).Updates(map[string]interface{}{
+			"location_id": nil,
+			"version":     gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&source).Error; err != nil {
+			return err
+		}
+		return enqueueEvent(tx, "box", source.ID, "box.deleted", map[string]interface{}{
+			"box_id":    source.ID,
+			"user_id":   source.UserID,
+			"merged_to": targetBoxID,
+		})
+	})
+}
+
+func (r *GormItemMoveRepository) SplitBox(ctx context.Context, newBox *models.Box, fromBoxID uuid.UUID, itemIDs []uuid.UUID, actorID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockBoxes(tx, fromBoxID)
+		if err != nil {
+			return err
+		}
+		// The new box takes the status and location the original has now
+		newBox.Status = locked[0].Status
+		newBox.LocationID = locked[0].LocationID
+		if err := moveBoxLoad(tx, nil, newBox.LocationID); err != nil {
+			return err
+		}
+		if err := tx.Create(newBox).Error; err != nil {
+			if isBoxCodeConflict(err) {
+				return repository.ErrBoxCodeTaken
+			}
+			return err
+		}
+		err = enqueueEvent(tx, "box", newBox.ID, "box.created", map[string]interface{}{
+			"box_id":       newBox.ID,
+			"user_id":      newBox.UserID,
+			"packing_mode": newBox.PackingMode,
+			"status":       newBox.Status,
+			"split_from":   fromBoxID,
+		})
+		if err != nil {
+			return err
+		}
+		return moveItems(tx, fromBoxID, newBox.ID, itemIDs, "split", actorID)
+	})
+}
+
+func (r *GormItemMoveRepository) ListByItemID(ctx context.Context, itemID uuid.UUID) ([]models.ItemMove, error) {
+	var moves []models.ItemMove
+	err := r.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("created_at ASC").
+		Find(&moves).Error
+	return moves, err
+}
+
+// lockBoxes locks the boxes for the rest of the transaction and bumps their
+// versions, since their contents are about to change. Boxes are locked in ID
+// order so that concurrent moves between the same boxes cannot deadlock. The
+// callers checked the boxes before, but a box may have changed since, so the
+// locked rows are checked again.
+func lockBoxes(tx *gorm.DB, ids ...uuid.UUID) ([]models.Box, error) {
+	var boxes []models.Box
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&boxes).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(boxes) != len(ids) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	for _, box := range boxes {
+		if box.PackingMode != "sort" {
+			return nil, repository.ErrBoxNotSortPacked
+		}
+		if box.Status == "returned" || box.Status == "disposed" {
+			return nil, repository.ErrBoxClosed
+		}
+	}
+	err = tx.Model(&models.Box{}).
+		Where("id IN ?", ids).
+		Update("version", gorm.Expr("version + 1")).Error
+	return boxes, err
+}
+
+// moveItems moves the items into toBoxID and records the move in each item's
+// history and the change feed.
+func moveItems(tx *gorm.DB, fromBoxID, toBoxID uuid.UUID, itemIDs []uuid.UUID, reason string, actorID uuid.UUID) error {
+	result := tx.Model(&models.Item{}).
+		Where("id IN ? AND box_id = ?", itemIDs, fromBoxID).
+		Updates(map[string]interface{}{
+			"box_id":  toBoxID,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != int64(len(itemIDs)) {
+		return repository.ErrItemsNotInBox
+	}
+
+	moves := make([]models.ItemMove, len(itemIDs))
+	for i, itemID := range itemIDs {
+		moves[i] = models.ItemMove{
+			ID:        uuid.New(),
+			ItemID:    itemID,
+			FromBoxID: fromBoxID,
+			ToBoxID:   toBoxID,
+			Reason:    reason,
+			ActorID:   actorID,
+		}
+	}
+	if err := tx.CreateInBatches(&moves, 100).Error; err != nil {
+		return err
+	}
+
+	userID, err := boxOwner(tx, toBoxID)
+	if err != nil {
+		return err
+	}
+	for _, move := range moves {
+		err := enqueueEvent(tx, "item", move.ItemID, "item.moved", map[string]interface{}{
+			"item_id":     move.ItemID,
+			"user_id":     userID,
+			"from_box_id": fromBoxID,
+			"to_box_id":   toBoxID,
+			"reason":      reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
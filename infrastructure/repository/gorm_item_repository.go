@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"github.com/sandroJayas/storage-service/domain/repository"
 	"github.com/sandroJayas/storage-service/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type GormItemRepository struct {
@@ -18,18 +20,62 @@ func NewGormItemRepository(db *gorm.DB) *GormItemRepository {
 }
 
 func (r *GormItemRepository) Create(ctx context.Context, item *models.Item) error {
-	return r.db.WithContext(ctx).Create(item).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		userID, err := boxOwner(tx, item.BoxID)
+		if err != nil {
+			return err
+		}
+		if err := recordInitialQuantities(tx, []models.Item{*item}, userID); err != nil {
+			return err
+		}
+		return enqueueItemEvent(tx, item, "item.added")
+	})
+}
+
+func (r *GormItemRepository) CreateBatch(ctx context.Context, items []models.Item) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(&items, 100).Error; err != nil {
+			return err
+		}
+		owners := map[uuid.UUID]uuid.UUID{}
+		for _, item := range items {
+			if _, ok := owners[item.BoxID]; ok {
+				continue
+			}
+			userID, err := boxOwner(tx, item.BoxID)
+			if err != nil {
+				return err
+			}
+			owners[item.BoxID] = userID
+			if err := recordInitialQuantities(tx, itemsInBox(items, item.BoxID), userID); err != nil {
+				return err
+			}
+		}
+		for i := range items {
+			if err := enqueueItemEvent(tx, &items[i], "item.added"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *GormItemRepository) ListByBoxID(ctx context.Context, boxID uuid.UUID) ([]models.Item, error) {
 	var items []models.Item
-	err := r.db.WithContext(ctx).Where("box_id = ?", boxID).Find(&items).Error
+	err := r.db.WithContext(ctx).
+		Preload("Images", orderedImages).
+		Where("box_id = ?", boxID).
+		Find(&items).Error
 	return items, err
 }
 
 func (r *GormItemRepository) GetByID(ctx context.Context, itemID uuid.UUID, userID uuid.UUID) (*models.Item, error) {
 	var item models.Item
 	err := r.db.WithContext(ctx).
+		Preload("Images", orderedImages).
 		Joins("JOIN boxes ON boxes.id = items.box_id").
 		Where("items.id = ? AND boxes.user_id = ?", itemID, userID).
 		First(&item).Error
@@ -39,10 +85,252 @@ func (r *GormItemRepository) GetByID(ctx context.Context, itemID uuid.UUID, user
 	return &item, err
 }
 
-func (r *GormItemRepository) Update(ctx context.Context, item *models.Item) error {
-	return r.db.WithContext(ctx).Save(item).Error
+// GetAnyByID looks an item up regardless of its box's owner, for employee workflows.
+func (r *GormItemRepository) GetAnyByID(ctx context.Context, itemID uuid.UUID) (*models.Item, error) {
+	var item models.Item
+	err := r.db.WithContext(ctx).
+		Preload("Images", orderedImages).
+		Where("id = ?", itemID).
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Update writes the item's fields if it is still at item.Version and bumps the
+// version. It returns repository.ErrVersionConflict if another write came first.
+func (r *GormItemRepository) Update(ctx context.Context, item *models.Item, actorID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Item
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "quantity", "version").
+			Where("id = ?", item.ID).
+			First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repository.ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+		if current.Version != item.Version {
+			return repository.ErrVersionConflict
+		}
+		if delta := item.Quantity - current.Quantity; delta != 0 {
+			err := tx.Create(&models.ItemQuantityMovement{
+				ID:            uuid.New(),
+				ItemID:        item.ID,
+				Delta:         delta,
+				QuantityAfter: item.Quantity,
+				Reason:        "correction",
+				ActorID:       actorID,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.Item{}).
+			Where("id = ? AND version = ?", item.ID, item.Version).
+			Updates(map[string]interface{}{
+				"name":        item.Name,
+				"description": item.Description,
+				"quantity":    item.Quantity,
+				"image_url":   item.ImageURL,
+				"version":     gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrVersionConflict
+		}
+		item.Version++
+		return enqueueItemEvent(tx, item, "item.updated")
+	})
+}
+
+func (r *GormItemRepository) AdjustQuantity(ctx context.Context, movement *models.ItemQuantityMovement, version *int) (*models.Item, error) {
+	var item models.Item
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", movement.ItemID).First(&item).Error
+		if err != nil {
+			return err
+		}
+		if version != nil && item.Version != *version {
+			return repository.ErrVersionConflict
+		}
+		return applyQuantityMovement(tx, &item, movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// applyQuantityMovement adds movement.Delta to the locked item, records the
+// movement and emits an item.quantity_adjusted event.
+func applyQuantityMovement(tx *gorm.DB, item *models.Item, movement *models.ItemQuantityMovement) error {
+	quantity := item.Quantity + movement.Delta
+	if quantity < 0 {
+		return repository.ErrNegativeQuantity
+	}
+
+	err := tx.Model(item).Updates(map[string]interface{}{
+		"quantity": quantity,
+		"version":  gorm.Expr("version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+	item.Quantity = quantity
+	item.Version++
+
+	movement.QuantityAfter = quantity
+	if err := tx.Create(movement).Error; err != nil {
+		return err
+	}
+
+	userID, err := boxOwner(tx, item.BoxID)
+	if err != nil {
+		return err
+	}
+	return enqueueEvent(tx, "item", item.ID, "item.quantity_adjusted", map[string]interface{}{
+		"item_id":  item.ID,
+		"box_id":   item.BoxID,
+		"user_id":  userID,
+		"delta":    movement.Delta,
+		"quantity": quantity,
+		"reason":   movement.Reason,
+		"actor_id": movement.ActorID,
+	})
 }
 
-func (r *GormItemRepository) Delete(ctx context.Context, itemID uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.Item{}, itemID).Error
+func (r *GormItemRepository) ListQuantityMovements(ctx context.Context, itemID uuid.UUID) ([]models.ItemQuantityMovement, error) {
+	var movements []models.ItemQuantityMovement
+	err := r.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("created_at ASC").
+		Find(&movements).Error
+	return movements, err
+}
+
+// recordInitialQuantities opens the quantity ledger of newly created items.
+func recordInitialQuantities(tx *gorm.DB, items []models.Item, actorID uuid.UUID) error {
+	var movements []models.ItemQuantityMovement
+	for _, item := range items {
+		if item.Quantity == 0 {
+			continue
+		}
+		movements = append(movements, models.ItemQuantityMovement{
+			ID:            uuid.New(),
+			ItemID:        item.ID,
+			Delta:         item.Quantity,
+			QuantityAfter: item.Quantity,
+			Reason:        "initial",
+			ActorID:       actorID,
+		})
+	}
+	if len(movements) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(&movements, 100).Error
+}
+
+func itemsInBox(items []models.Item, boxID uuid.UUID) []models.Item {
+	var inBox []models.Item
+	for _, item := range items {
+		if item.BoxID == boxID {
+			inBox = append(inBox, item)
+		}
+	}
+	return inBox
+}
+
+// Delete deletes the item. With a version it only deletes the item at that
+// version and returns repository.ErrVersionConflict otherwise.
+func (r *GormItemRepository) Delete(ctx context.Context, itemID uuid.UUID, version *int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item models.Item
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", itemID).First(&item).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if version != nil {
+				return repository.ErrVersionConflict
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if version != nil && item.Version != *version {
+			return repository.ErrVersionConflict
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		return enqueueItemEvent(tx, &item, "item.deleted")
+	})
+}
+
+// Search ranks the user's items in non-deleted boxes against a web-search style
+// query over the items.search_vector column.
+func (r *GormItemRepository) Search(ctx context.Context, userID uuid.UUID, query string, limit, offset int) ([]repository.ItemSearchHit, int64, error) {
+	base := r.db.WithContext(ctx).
+		Table("items").
+		Joins("JOIN boxes ON boxes.id = items.box_id AND boxes.deleted_at IS NULL").
+		Where("items.deleted_at IS NULL AND boxes.user_id = ?", userID).
+		Where("items.search_vector @@ websearch_to_tsquery('english', ?)", query)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var hits []repository.ItemSearchHit
+	err := base.Session(&gorm.Session{}).
+		Select("items.*, boxes.status AS box_status, boxes.location_id, storage_locations.name AS location_name, "+
+			"ts_rank(items.search_vector, websearch_to_tsquery('english', ?)) AS rank", query).
+		Joins("LEFT JOIN storage_locations ON storage_locations.id = boxes.location_id").
+		Order("rank DESC, items.id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&hits).Error
+	if err != nil || len(hits) == 0 {
+		return hits, total, err
+	}
+
+	// Scan does not preload, so attach the images of the page in one query
+	ids := make([]uuid.UUID, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	var images []models.ItemImage
+	if err := r.db.WithContext(ctx).
+		Where("item_id IN ?", ids).
+		Order("position ASC").
+		Find(&images).Error; err != nil {
+		return nil, 0, err
+	}
+	byItem := make(map[uuid.UUID][]models.ItemImage, len(hits))
+	for _, image := range images {
+		byItem[image.ItemID] = append(byItem[image.ItemID], image)
+	}
+	for i := range hits {
+		hits[i].Images = byItem[hits[i].ID]
+	}
+	return hits, total, nil
+}
+
+func enqueueItemEvent(tx *gorm.DB, item *models.Item, eventType string) error {
+	userID, err := boxOwner(tx, item.BoxID)
+	if err != nil {
+		return err
+	}
+	return enqueueEvent(tx, "item", item.ID, eventType, map[string]interface{}{
+		"item_id":  item.ID,
+		"box_id":   item.BoxID,
+		"user_id":  userID,
+		"name":     item.Name,
+		"quantity": item.Quantity,
+	})
 }
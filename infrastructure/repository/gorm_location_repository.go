@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormLocationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLocationRepository(db *gorm.DB) *GormLocationRepository {
+	return &GormLocationRepository{db}
+}
+
+func (r *GormLocationRepository) Create(ctx context.Context, location *models.StorageLocation) error {
+	return r.db.WithContext(ctx).Create(location).Error
+}
+
+func (r *GormLocationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.StorageLocation, error) {
+	var location models.StorageLocation
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&location).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *GormLocationRepository) FindAll(ctx context.Context) ([]models.StorageLocation, error) {
+	var locations []models.StorageLocation
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&locations).Error
+	return locations, err
+}
+
+// Update applies the given column updates. A new capacity is only accepted if it
+// still fits the boxes currently at the location.
+func (r *GormLocationRepository) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var location models.StorageLocation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&location).Error
+		if err != nil {
+			return err
+		}
+		if capacity, ok := updates["capacity"].(int); ok && capacity < location.CurrentLoad {
+			return repository.ErrLocationFull
+		}
+		return tx.Model(&location).Updates(updates).Error
+	})
+}
+
+func (r *GormLocationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var location models.StorageLocation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&location).Error
+		if err != nil {
+			return err
+		}
+		if location.CurrentLoad > 0 {
+			return repository.ErrLocationInUse
+		}
+		return tx.Delete(&location).Error
+	})
+}
+
+// AssignBox moves a box to locationID, or removes it from its location when
+// locationID is nil. The load of the old and new locations is adjusted in the
+// same transaction, and placement fails with ErrLocationFull at capacity.
+func (r *GormLocationRepository) AssignBox(ctx context.Context, boxID uuid.UUID, locationID *uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var box models.Box
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", boxID).First(&box).Error
+		if err != nil {
+			return err
+		}
+		if sameLocation(box.LocationID, locationID) {
+			return nil
+		}
+		if err := moveBoxLoad(tx, box.LocationID, locationID); err != nil {
+			return err
+		}
+
+		return tx.Model(&box).Updates(map[string]interface{}{
+			"location_id": locationID,
+			"version":     gorm.Expr("version + 1"),
+		}).Error
+	})
+}
+
+// moveBoxLoad moves one unit of load from the box's current location to the new
+// one, either of which may be nil. It returns repository.ErrLocationFull when
+// the new location has no room left. The box row itself is left to the caller.
+func moveBoxLoad(tx *gorm.DB, from, to *uuid.UUID) error {
+	if to != nil {
+		var location models.StorageLocation
+		if err := tx.Where("id = ?", *to).First(&location).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.StorageLocation{}).
+			Where("id = ? AND current_load < capacity", *to).
+			Update("current_load", gorm.Expr("current_load + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrLocationFull
+		}
+	}
+
+	if from != nil {
+		return tx.Model(&models.StorageLocation{}).
+			Where("id = ?", *from).
+			Update("current_load", gorm.Expr("current_load - 1")).Error
+	}
+	return nil
+}
+
+func sameLocation(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOrderRepository(db *gorm.DB) *GormOrderRepository {
+	return &GormOrderRepository{db}
+}
+
+// Create inserts the order. The unique index on open orders per box turns a
+// concurrent second order into repository.ErrOpenOrderExists.
+func (r *GormOrderRepository) Create(ctx context.Context, order *models.StorageOrder) error {
+	err := r.db.WithContext(ctx).Create(order).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation && pgErr.ConstraintName == "idx_storage_orders_open_box" {
+		return repository.ErrOpenOrderExists
+	}
+	return err
+}
+
+func (r *GormOrderRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.StorageOrder, error) {
+	var order models.StorageOrder
+	err := r.db.WithContext(ctx).Preload("Items").Where("id = ?", id).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *GormOrderRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.StorageOrder, error) {
+	var orders []models.StorageOrder
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("user_id = ?", userID).
+		Order("scheduled_date ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// FindByStatus lists orders across all users. An empty status returns every order.
+func (r *GormOrderRepository) FindByStatus(ctx context.Context, status string) ([]models.StorageOrder, error) {
+	var orders []models.StorageOrder
+	query := r.db.WithContext(ctx).Preload("Items")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("scheduled_date ASC").Find(&orders).Error
+	return orders, err
+}
+
+// FindOpenByBoxID returns the order for the box that is still requested or in progress, if any.
+func (r *GormOrderRepository) FindOpenByBoxID(ctx context.Context, boxID uuid.UUID) (*models.StorageOrder, error) {
+	var order models.StorageOrder
+	err := r.db.WithContext(ctx).
+		Where("box_id = ? AND status IN ?", boxID, []string{"requested", "in_progress"}).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *GormOrderRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.StorageOrder{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *GormOrderRepository) CompletePartialReturn(ctx context.Context, order *models.StorageOrder, actorID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only one request may complete the order and take its items out
+		var current models.StorageOrder
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", order.ID, order.Status).
+			First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repository.ErrVersionConflict
+		}
+		if err != nil {
+			return err
+		}
+
+		// Lock in a stable order so concurrent returns from one box cannot deadlock
+		lines := append([]models.StorageOrderItem(nil), order.Items...)
+		sort.Slice(lines, func(i, j int) bool { return lines[i].ItemID.String() < lines[j].ItemID.String() })
+
+		for _, line := range lines {
+			var item models.Item
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", line.ItemID).First(&item).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return repository.ErrItemsNotInBox
+			}
+			if err != nil {
+				return err
+			}
+			if item.BoxID != order.BoxID {
+				return repository.ErrItemsNotInBox
+			}
+
+			err = applyQuantityMovement(tx, &item, &models.ItemQuantityMovement{
+				ID:      uuid.New(),
+				ItemID:  item.ID,
+				Delta:   -line.Quantity,
+				Reason:  "retrieved",
+				Note:    "return order " + order.ID.String(),
+				ActorID: actorID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.StorageOrder{}).Where("id = ?", order.ID).Update("status", "completed").Error
+	})
+}
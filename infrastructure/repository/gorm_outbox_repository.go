@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOutboxRepository(db *gorm.DB) *GormOutboxRepository {
+	return &GormOutboxRepository{db}
+}
+
+// ClaimPending returns up to limit unpublished events that are due, and pushes
+// their next attempt out by lease so other relays skip them while they are being
+// published. If the relay dies mid-batch the lease expires and the events are retried.
+func (r *GormOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ?", now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(events))
+		for _, e := range events {
+			ids = append(ids, e.ID)
+		}
+		return tx.Model(&models.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return events, err
+}
+
+func (r *GormOutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"published_at": time.Now().UTC(),
+			"last_error":   "",
+		}).Error
+}
+
+func (r *GormOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": nextAttemptAt.UTC(),
+			"last_error":      lastError,
+		}).Error
+}
+
+// enqueueEvent writes an outbox event using tx, so it commits or rolls back
+// together with the change it describes.
+func enqueueEvent(tx *gorm.DB, aggregateType string, aggregateID uuid.UUID, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+		NextAttemptAt: time.Now().UTC(),
+	}).Error
+}
+
+// boxOwner returns the user_id of a box, including soft-deleted ones.
+func boxOwner(tx *gorm.DB, boxID uuid.UUID) (uuid.UUID, error) {
+	var box models.Box
+	err := tx.Unscoped().Select("id", "user_id").Where("id = ?", boxID).First(&box).Error
+	return box.UserID, err
+}
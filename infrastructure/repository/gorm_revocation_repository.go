@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormRevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRevocationRepository(db *gorm.DB) *GormRevocationRepository {
+	return &GormRevocationRepository{db}
+}
+
+func (r *GormRevocationRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "jti"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"expires_at": gorm.Expr("GREATEST(revoked_tokens.expires_at, EXCLUDED.expires_at)"),
+			}),
+		}).
+		Create(&models.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+func (r *GormRevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.RevokedToken{}).
+		Where("jti = ?", jti).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormRevocationRepository) RevokeUserTokens(ctx context.Context, userID uuid.UUID, before time.Time) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"revoked_before": gorm.Expr("GREATEST(user_token_cutoffs.revoked_before, EXCLUDED.revoked_before)"),
+				"updated_at":     gorm.Expr("EXCLUDED.updated_at"),
+			}),
+		}).
+		Create(&models.UserTokenCutoff{UserID: userID, RevokedBefore: before}).Error
+}
+
+func (r *GormRevocationRepository) UserTokenCutoff(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	var cutoff models.UserTokenCutoff
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&cutoff).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return cutoff.RevokedBefore, err
+}
+
+func (r *GormRevocationRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ?", now).
+		Delete(&models.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
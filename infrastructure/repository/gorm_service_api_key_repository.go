@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormServiceAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormServiceAPIKeyRepository(db *gorm.DB) *GormServiceAPIKeyRepository {
+	return &GormServiceAPIKeyRepository{db}
+}
+
+func (r *GormServiceAPIKeyRepository) Create(ctx context.Context, key *models.ServiceAPIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *GormServiceAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.ServiceAPIKey, error) {
+	var key models.ServiceAPIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *GormServiceAPIKeyRepository) FindAll(ctx context.Context) ([]models.ServiceAPIKey, error) {
+	var keys []models.ServiceAPIKey
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *GormServiceAPIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) (*models.ServiceAPIKey, error) {
+	var key models.ServiceAPIKey
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&key).Error; err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return nil
+		}
+		now := time.Now()
+		key.RevokedAt = &now
+		return tx.Model(&key).Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
@@ -1,18 +1,43 @@
 package middleware
 
 import (
-	"fmt"
+	"context"
 	"github.com/google/uuid"
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// RevocationChecker reports whether a verified access token has been revoked
+// before it expired. issuedAt is zero and jti empty when the token lacks them.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, userID uuid.UUID, jti string, issuedAt time.Time) (bool, error)
+}
+
+// ServiceKeyAuthenticator looks up the service API key sent in X-API-Key. It
+// returns a nil key without an error if the key is unknown, expired or revoked.
+type ServiceKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.ServiceAPIKey, error)
+}
+
+// AuthMiddleware accepts requests with a bearer token that verifier accepts and
+// revocations, if set, does not report as revoked, and sets the caller's
+// user_id, account_type and principal. With apiKeys set, requests carrying an
+// X-API-Key header are authenticated as services instead; those get a
+// principal and account_type but no user_id, since a service is not a user.
+func AuthMiddleware(verifier *TokenVerifier, revocations RevocationChecker, apiKeys ServiceKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if key := c.GetHeader("X-API-Key"); key != "" && apiKeys != nil {
+			authenticateService(c, apiKeys, key)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid token"})
@@ -21,13 +46,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims := jwt.MapClaims{}
-		token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
+		claims, err := verifier.Verify(c.Request.Context(), tokenStr)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error":   "Invalid token",
@@ -35,12 +54,6 @@ func AuthMiddleware() gin.HandlerFunc {
 			})
 			return
 		}
-		if !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token",
-			})
-			return
-		}
 
 		userIDStr, ok := claims["user_id"].(string)
 		if !ok {
@@ -54,6 +67,24 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if revocations != nil {
+			jti, _ := claims["jti"].(string)
+			var issuedAt time.Time
+			if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+				issuedAt = iat.Time
+			}
+			revoked, err := revocations.IsRevoked(c.Request.Context(), userID, jti, issuedAt)
+			if err != nil {
+				utils.Logger.Error("Failed to check token revocation", zap.String("user_id", userID.String()), zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "could not check token revocation"})
+				return
+			}
+			if revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+				return
+			}
+		}
+
 		accountType, ok := claims["account_type"].(string)
 		if !ok {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "account_type claim is missing or invalid"})
@@ -62,6 +93,78 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		c.Set("user_id", userID)
 		c.Set("account_type", accountType)
+		c.Set("principal", policy.Principal{UserID: userID, Role: policy.ParseRole(accountType)})
+		c.Next()
+	}
+}
+
+// authenticateService sets a service principal holding the key's scopes as its
+// permissions and logs the call once it has been handled.
+func authenticateService(c *gin.Context, apiKeys ServiceKeyAuthenticator, plain string) {
+	key, err := apiKeys.Authenticate(c.Request.Context(), plain)
+	if err != nil {
+		utils.Logger.Error("Failed to look up service API key", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "could not check API key"})
+		return
+	}
+	if key == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid, expired or revoked API key"})
+		return
+	}
+
+	scopes := make([]policy.Permission, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, policy.Permission(scope))
+	}
+	c.Set("account_type", string(policy.Service))
+	c.Set("principal", policy.Principal{UserID: key.ID, Role: policy.Service, Scopes: scopes})
+
+	start := time.Now()
+	c.Next()
+	utils.Logger.Info("Service API call",
+		zap.String("service", key.Name),
+		zap.String("api_key_id", key.ID.String()),
+		zap.String("api_key_prefix", key.Prefix),
+		zap.String("method", c.Request.Method),
+		zap.String("path", c.Request.URL.Path),
+		zap.Int("status", c.Writer.Status()),
+		zap.Duration("duration", time.Since(start)))
+}
+
+// CurrentPrincipal returns the caller set by AuthMiddleware.
+func CurrentPrincipal(c *gin.Context) policy.Principal {
+	return c.MustGet("principal").(policy.Principal)
+}
+
+// RequireUser guards routes that act on the caller's own boxes and items and
+// read the caller's user_id. Services own none, so service principals are
+// rejected with a 403 unless they hold one of perms, which grant the route's
+// action on any user's resources. It must run after AuthMiddleware.
+func RequireUser(perms ...policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := CurrentPrincipal(c)
+		if principal.Role == policy.Service {
+			for _, perm := range perms {
+				if principal.Can(perm) {
+					c.Next()
+					return
+				}
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "service API keys cannot act as a customer"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Require rejects requests whose role lacks the permission with a 403.
+// It must run after AuthMiddleware.
+func Require(perm policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := CurrentPrincipal(c).Authorize(perm); err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.Next()
 	}
 }
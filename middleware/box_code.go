@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ResolveBoxCode lets /boxes/:id routes take a short box code in place of the
+// UUID. A code is swapped for the box ID before the handler runs, so handlers
+// and their ownership checks only ever see UUIDs. Unknown codes get a 404.
+func ResolveBoxCode(repo repository.BoxRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		param := c.Param("id")
+		if param == "" {
+			c.Next()
+			return
+		}
+		if _, err := uuid.Parse(param); err == nil {
+			c.Next()
+			return
+		}
+		code, ok := utils.ParseBoxCode(param)
+		if !ok {
+			// Leave it to the handler to reject the malformed ID
+			c.Next()
+			return
+		}
+
+		boxID, err := repo.FindIDByCode(c.Request.Context(), code)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "box not found or not accessible"})
+			return
+		}
+		if err != nil {
+			utils.Logger.Error("Failed to resolve box code", zap.String("code", code), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		for i := range c.Params {
+			if c.Params[i].Key == "id" {
+				c.Params[i].Value = boxID.String()
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandroJayas/storage-service/config"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to
+// retry. The first request with a key runs normally and its response is stored
+// per user; later requests with the same key and payload get that response
+// replayed, while a different payload under the same key is rejected with 422.
+// Other methods and requests without the header pass through untouched.
+// It must run after AuthMiddleware.
+func Idempotency(repo repository.IdempotencyRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		// The body is hashed before the handler runs, so it is buffered, up to a limit
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.IdempotencyMaxBodyBytes))
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit)})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// Keys are scoped per caller; for services that is the API key's ID
+		userID := CurrentPrincipal(c).UserID
+		hash := sha256.New()
+		target := c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			target += "?" + c.Request.URL.RawQuery
+		}
+		hash.Write([]byte(c.Request.Method + " " + target + "\n"))
+		hash.Write(body)
+
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: hex.EncodeToString(hash.Sum(nil)),
+			ExpiresAt:   time.Now().Add(config.AppConfig.IdempotencyKeyTTL),
+		}
+		existing, err := repo.Reserve(c.Request.Context(), record)
+		if err != nil {
+			utils.Logger.Error("failed to reserve idempotency key", zap.String("user_id", userID.String()), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to check Idempotency-Key"})
+			return
+		}
+		if existing != nil {
+			replayIdempotentResponse(c, existing, record.RequestHash)
+			return
+		}
+
+		// The record is finished even if the client has gone away or the handler
+		// panics, otherwise retries would be answered with 409 until it expires
+		finishCtx := context.WithoutCancel(c.Request.Context())
+		finished := false
+		defer func() {
+			if finished {
+				return
+			}
+			if err := repo.Release(finishCtx, userID, key); err != nil {
+				utils.Logger.Error("failed to release idempotency key", zap.String("user_id", userID.String()), zap.String("key", key), zap.Error(err))
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Server errors are not remembered so that the client can retry them
+		status := recorder.Status()
+		if status < http.StatusInternalServerError {
+			err = repo.Complete(finishCtx, userID, key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+			if err != nil {
+				utils.Logger.Error("failed to store idempotent response", zap.String("user_id", userID.String()), zap.String("key", key), zap.Error(err))
+			}
+			finished = err == nil
+		}
+	}
+}
+
+func replayIdempotentResponse(c *gin.Context, existing *models.IdempotencyKey, requestHash string) {
+	switch {
+	case existing.RequestHash != requestHash:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+	case existing.ResponseStatus == nil:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "a request with this Idempotency-Key is still being processed"})
+	default:
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(*existing.ResponseStatus, existing.ContentType, existing.ResponseBody)
+		c.Abort()
+	}
+}
+
+// responseRecorder keeps a copy of the response body as it is written.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/config"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIdempotencyRepository records the calls made to it and the state of
+// the context each was made with.
+type fakeIdempotencyRepository struct {
+	records   map[string]*models.IdempotencyKey
+	completed []error
+	released  []error
+}
+
+func (r *fakeIdempotencyRepository) Reserve(_ context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	if existing, ok := r.records[record.Key]; ok {
+		return existing, nil
+	}
+	r.records[record.Key] = record
+	return nil, nil
+}
+
+func (r *fakeIdempotencyRepository) Complete(ctx context.Context, _ uuid.UUID, key string, status int, contentType string, body []byte) error {
+	r.completed = append(r.completed, ctx.Err())
+	record := r.records[key]
+	record.ResponseStatus = &status
+	record.ContentType = contentType
+	record.ResponseBody = body
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) Release(ctx context.Context, _ uuid.UUID, key string) error {
+	r.released = append(r.released, ctx.Err())
+	delete(r.records, key)
+	return nil
+}
+
+func (r *fakeIdempotencyRepository) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotency(t *testing.T) {
+	utils.InitLogger()
+	gin.SetMode(gin.TestMode)
+	config.AppConfig = &config.EnvConfig{IdempotencyKeyTTL: time.Hour, IdempotencyMaxBodyBytes: 64}
+	userID := uuid.New()
+
+	newRouter := func(repo *fakeIdempotencyRepository, handler gin.HandlerFunc) *gin.Engine {
+		r := gin.New()
+		r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}))
+		r.Use(func(c *gin.Context) {
+			c.Set("user_id", userID)
+			c.Set("principal", policy.Principal{UserID: userID, Role: policy.Customer})
+		})
+		r.Use(Idempotency(repo))
+		r.POST("/boxes", handler)
+		return r
+	}
+	post := func(r *gin.Engine, ctx context.Context, target, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"packing_mode":"self"}`)).WithContext(ctx)
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("stores the response when the client has gone away", func(t *testing.T) {
+		repo := &fakeIdempotencyRepository{records: map[string]*models.IdempotencyKey{}}
+		ctx, cancel := context.WithCancel(context.Background())
+		r := newRouter(repo, func(c *gin.Context) {
+			cancel()
+			c.JSON(http.StatusCreated, gin.H{"id": "1"})
+		})
+
+		post(r, ctx, "/boxes", "k1")
+		if assert.Len(t, repo.completed, 1) {
+			assert.NoError(t, repo.completed[0])
+		}
+		assert.Empty(t, repo.released)
+	})
+
+	t.Run("releases the key when the handler panics", func(t *testing.T) {
+		repo := &fakeIdempotencyRepository{records: map[string]*models.IdempotencyKey{}}
+		r := newRouter(repo, func(c *gin.Context) {
+			panic("boom")
+		})
+
+		w := post(r, context.Background(), "/boxes", "k1")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Len(t, repo.released, 1)
+		assert.Empty(t, repo.records, "a retry must not be answered with 409")
+	})
+
+	t.Run("query string is part of the request", func(t *testing.T) {
+		repo := &fakeIdempotencyRepository{records: map[string]*models.IdempotencyKey{}}
+		r := newRouter(repo, func(c *gin.Context) {
+			c.JSON(http.StatusCreated, gin.H{"id": "1"})
+		})
+
+		assert.Equal(t, http.StatusCreated, post(r, context.Background(), "/boxes?format=a", "k1").Code)
+		w := post(r, context.Background(), "/boxes?format=a", "k1")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, http.StatusUnprocessableEntity, post(r, context.Background(), "/boxes?format=b", "k1").Code)
+	})
+
+	t.Run("rejects bodies over the limit before buffering them", func(t *testing.T) {
+		repo := &fakeIdempotencyRepository{records: map[string]*models.IdempotencyKey{}}
+		r := newRouter(repo, func(c *gin.Context) {
+			t.Error("handler must not run")
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/boxes", strings.NewReader(strings.Repeat("x", 65)))
+		req.Header.Set("Idempotency-Key", "k1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Empty(t, repo.records)
+	})
+}
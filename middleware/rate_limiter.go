@@ -1,88 +1,101 @@
 package middleware
 
 import (
-	"github.com/sandroJayas/storage-service/config"
+	"fmt"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/config"
+	"github.com/sandroJayas/storage-service/domain/ratelimit"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
 )
 
-// Limits unauthenticated routes per IP address
-// Allows:
-// 1 request per second
-// up to 3 requests burst
-// Cleans up idle IPs every 5 mins
-
-type clientLimiter struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
+// RateLimiter limits requests per route group with the limit rules picks for
+// the caller's account type. Callers are counted by user_id when AuthMiddleware
+// ran before, and by client IP otherwise. ClientIP adds a per-IP limit that
+// runs before authentication.
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+	rules   *ratelimit.Rules
 }
 
-var (
-	clients = make(map[string]*clientLimiter)
-	mu      sync.Mutex
-
-	// Cleanup old clients periodically
-	cleanupInterval = time.Minute * 5
-)
-
-func init() {
-	go cleanupClients()
+func NewRateLimiter(limiter ratelimit.Limiter, rules *ratelimit.Rules) *RateLimiter {
+	return &RateLimiter{limiter: limiter, rules: rules}
 }
 
-func cleanupClients() {
-	for {
-		time.Sleep(cleanupInterval)
-		mu.Lock()
-		for ip, cl := range clients {
-			if time.Since(cl.lastSeen) > time.Minute*10 {
-				delete(clients, ip)
-			}
+// Group returns the middleware for the routes of group. Every response carries
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy
+// headers, and rejected requests get a 429 with Retry-After. If the limiter
+// fails the request is let through, so an outage of a shared backend does not
+// take the API down with it. A nil RateLimiter and APP_ENV=testing disable limits.
+func (rl *RateLimiter) Group(group string) gin.HandlerFunc {
+	if rl == nil || config.AppConfig.AppEnv == "testing" {
+		return func(c *gin.Context) {
+			c.Next()
 		}
-		mu.Unlock()
 	}
-}
-
-func getLimiterForIP(ip string) *rate.Limiter {
-	mu.Lock()
-	defer mu.Unlock()
 
-	cl, exists := clients[ip]
-	if !exists {
-		limiter := rate.NewLimiter(1, 3) // 1 request/sec with burst of 3
-		clients[ip] = &clientLimiter{
-			limiter:  limiter,
-			lastSeen: time.Now(),
+	return func(c *gin.Context) {
+		subject := "ip:" + c.ClientIP()
+		accountType := "anonymous"
+		if userID, ok := c.Get("user_id"); ok {
+			subject = "user:" + userID.(uuid.UUID).String()
+			accountType = c.GetString("account_type")
+		} else if principal, ok := c.Get("principal"); ok {
+			// Services have no user_id and are counted per API key
+			subject = "service:" + principal.(policy.Principal).UserID.String()
+			accountType = c.GetString("account_type")
 		}
-		return limiter
+		rl.limit(c, group, subject, accountType)
 	}
-
-	cl.lastSeen = time.Now()
-	return cl.limiter
 }
 
-// RateLimitMiddleware applies a rate limit per IP
-func RateLimitMiddleware() gin.HandlerFunc {
-	if config.AppConfig.AppEnv == "testing" {
+// ClientIP returns middleware that counts every request by client IP against
+// the limit of the "ip" group. It goes in front of AuthMiddleware, so requests
+// with a missing or bad token are limited too.
+func (rl *RateLimiter) ClientIP() gin.HandlerFunc {
+	if rl == nil || config.AppConfig.AppEnv == "testing" {
 		return func(c *gin.Context) {
 			c.Next()
 		}
 	}
 
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := getLimiterForIP(ip)
-
-		if !limiter.Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"error": "rate limit exceeded",
-			})
-			return
-		}
+		rl.limit(c, "ip", "ip:"+c.ClientIP(), "anonymous")
+	}
+}
 
+func (rl *RateLimiter) limit(c *gin.Context, group, subject, accountType string) {
+	limit := rl.rules.For(group, accountType)
+	res, err := rl.limiter.Allow(c.Request.Context(), "ratelimit:"+group+":"+subject, limit)
+	if err != nil {
+		utils.Logger.Warn("Rate limiter failed, allowing request",
+			zap.String("group", group),
+			zap.String("subject", subject),
+			zap.Error(err))
 		c.Next()
+		return
 	}
+
+	reset := strconv.Itoa(int(math.Ceil(res.ResetAfter.Seconds())))
+	c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Header("RateLimit-Reset", reset)
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Window/time.Second)))
+
+	if !res.Allowed {
+		c.Header("Retry-After", reset)
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error": "rate limit exceeded",
+		})
+		return
+	}
+
+	c.Next()
 }
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/config"
+	"github.com/sandroJayas/storage-service/domain/ratelimit"
+	"github.com/sandroJayas/storage-service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingLimiter allows limit.Requests requests per key and records the keys.
+type countingLimiter struct {
+	counts map[string]int
+	err    error
+}
+
+func (l *countingLimiter) Allow(_ context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if l.err != nil {
+		return ratelimit.Result{}, l.err
+	}
+	l.counts[key]++
+	remaining := limit.Requests - l.counts[key]
+	if remaining < 0 {
+		remaining = 0
+	}
+	return ratelimit.Result{
+		Allowed:    l.counts[key] <= limit.Requests,
+		Limit:      limit.Requests,
+		Remaining:  remaining,
+		ResetAfter: 1500 * time.Millisecond,
+	}, nil
+}
+
+func TestRateLimiter(t *testing.T) {
+	utils.InitLogger()
+	gin.SetMode(gin.TestMode)
+	config.AppConfig = &config.EnvConfig{AppEnv: "production"}
+	rules, err := ratelimit.ParseRules("*=2/1m,*:warehouse_staff=5/1m")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	newRouter := func(limiter ratelimit.Limiter, userID *uuid.UUID, accountType string) *gin.Engine {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			if userID != nil {
+				c.Set("user_id", *userID)
+				c.Set("account_type", accountType)
+			}
+		})
+		r.Use(NewRateLimiter(limiter, rules).Group("boxes"))
+		r.GET("/boxes", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r
+	}
+	get := func(r *gin.Engine) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/boxes", nil))
+		return w
+	}
+
+	t.Run("limits per user with headers", func(t *testing.T) {
+		limiter := &countingLimiter{counts: map[string]int{}}
+		userID := uuid.New()
+		r := newRouter(limiter, &userID, "customer")
+
+		w := get(r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", w.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+		assert.Empty(t, w.Header().Get("Retry-After"))
+
+		get(r)
+		w = get(r)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		assert.Equal(t, 3, limiter.counts["ratelimit:boxes:user:"+userID.String()])
+	})
+
+	t.Run("account type picks the limit", func(t *testing.T) {
+		userID := uuid.New()
+		w := get(newRouter(&countingLimiter{counts: map[string]int{}}, &userID, "warehouse_staff"))
+		assert.Equal(t, "5", w.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("falls back to the client IP", func(t *testing.T) {
+		limiter := &countingLimiter{counts: map[string]int{}}
+		get(newRouter(limiter, nil, ""))
+		assert.Equal(t, 1, limiter.counts["ratelimit:boxes:ip:192.0.2.1"])
+	})
+
+	t.Run("limiter failure lets requests through", func(t *testing.T) {
+		userID := uuid.New()
+		w := get(newRouter(&countingLimiter{err: errors.New("connection refused")}, &userID, "customer"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	})
+}
+
+func TestRateLimiterClientIPLimitsFailedAuth(t *testing.T) {
+	utils.InitLogger()
+	gin.SetMode(gin.TestMode)
+	config.AppConfig = &config.EnvConfig{AppEnv: "production"}
+	rules, err := ratelimit.ParseRules("*=100/1m,ip=2/1m")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	verifier, err := NewTokenVerifier(TokenVerifierConfig{HMACSecret: "secret"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	limiter := &countingLimiter{counts: map[string]int{}}
+	r := gin.New()
+	r.Use(NewRateLimiter(limiter, rules).ClientIP())
+	r.Use(AuthMiddleware(verifier, nil, nil))
+	r.GET("/boxes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	codes := make([]int, 3)
+	for i := range codes {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/boxes", nil)
+		req.Header.Set("Authorization", "Bearer not-a-token")
+		r.ServeHTTP(w, req)
+		codes[i] = w.Code
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	assert.Equal(t, 3, limiter.counts["ratelimit:ip:ip:192.0.2.1"])
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PublicKeySource looks up the public key a token names in its kid header.
+type PublicKeySource interface {
+	PublicKey(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// TokenVerifierConfig selects which access tokens are accepted.
+type TokenVerifierConfig struct {
+	// Keys verifies RS256 and ES256 tokens. Without it they are rejected.
+	Keys PublicKeySource
+	// HMACSecret verifies HS256 tokens. Without it they are rejected, which ends
+	// the migration to asymmetric keys.
+	HMACSecret string
+	// Issuer and Audience are required in the iss and aud claims when set
+	Issuer   string
+	Audience string
+	// Leeway is the clock skew allowed when checking exp, nbf and iat
+	Leeway time.Duration
+}
+
+// TokenVerifier checks the signature and standard claims of access tokens.
+type TokenVerifier struct {
+	keys       PublicKeySource
+	hmacSecret []byte
+	parser     *jwt.Parser
+}
+
+func NewTokenVerifier(cfg TokenVerifierConfig) (*TokenVerifier, error) {
+	var methods []string
+	if cfg.Keys != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+	if cfg.HMACSecret != "" {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no token keys configured: set a JWKS source or an HS256 secret")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithLeeway(cfg.Leeway)}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	return &TokenVerifier{
+		keys:       cfg.Keys,
+		hmacSecret: []byte(cfg.HMACSecret),
+		parser:     jwt.NewParser(opts...),
+	}, nil
+}
+
+// Verify returns the claims of a valid token.
+func (v *TokenVerifier) Verify(ctx context.Context, tokenStr string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := v.parser.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			return v.hmacSecret, nil
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" {
+				return nil, errors.New("token has no kid header")
+			}
+			return v.keys.PublicKey(ctx, kid)
+		default:
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticKeys map[string]crypto.PublicKey
+
+func (k staticKeys) PublicKey(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+	return key, nil
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id":      "6f1c1d2e-3a4b-4c5d-8e9f-0a1b2c3d4e5f",
+		"account_type": "customer",
+		"iss":          "https://auth.example.com",
+		"aud":          "storage-service",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestTokenVerifier(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	keys := staticKeys{"rsa-1": &rsaKey.PublicKey, "ec-1": &ecKey.PublicKey}
+
+	verifier, err := NewTokenVerifier(TokenVerifierConfig{
+		Keys:       keys,
+		HMACSecret: "shared-secret",
+		Issuer:     "https://auth.example.com",
+		Audience:   "storage-service",
+		Leeway:     time.Minute,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	verify := func(token string) error {
+		_, err := verifier.Verify(context.Background(), token)
+		return err
+	}
+
+	t.Run("RS256 and ES256 by kid", func(t *testing.T) {
+		assert.NoError(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims())))
+		assert.NoError(t, verify(sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims())))
+	})
+
+	t.Run("HS256 during migration", func(t *testing.T) {
+		assert.NoError(t, verify(sign(t, jwt.SigningMethodHS256, "", []byte("shared-secret"), validClaims())))
+		assert.Error(t, verify(sign(t, jwt.SigningMethodHS256, "", []byte("other-secret"), validClaims())))
+	})
+
+	t.Run("key must match kid", func(t *testing.T) {
+		assert.Error(t, verify(sign(t, jwt.SigningMethodRS256, "", rsaKey, validClaims())))
+		assert.Error(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-2", rsaKey, validClaims())))
+		otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		assert.Error(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-1", otherKey, validClaims())))
+	})
+
+	t.Run("issuer and audience", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "https://evil.example.com"
+		assert.Error(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims)))
+
+		claims = validClaims()
+		claims["aud"] = []string{"billing", "storage-service"}
+		assert.NoError(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims)))
+		delete(claims, "aud")
+		assert.Error(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims)))
+	})
+
+	t.Run("clock skew leeway", func(t *testing.T) {
+		claims := validClaims()
+		claims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+		assert.NoError(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims)))
+		claims["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+		assert.Error(t, verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims)))
+	})
+}
+
+func TestTokenVerifierWithoutHS256(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	verifier, err := NewTokenVerifier(TokenVerifierConfig{Keys: staticKeys{"rsa-1": &rsaKey.PublicKey}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = verifier.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "", []byte("shared-secret"), validClaims()))
+	assert.Error(t, err)
+
+	_, err = NewTokenVerifier(TokenVerifierConfig{})
+	assert.Error(t, err)
+}
@@ -34,6 +34,23 @@ SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: box_status_events; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.box_status_events (
+    id uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    box_id uuid NOT NULL,
+    from_status character varying(30) NOT NULL,
+    to_status character varying(30) NOT NULL,
+    actor_id uuid NOT NULL,
+    account_type character varying(30) NOT NULL,
+    note text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    location_id uuid
+);
+
+
 --
 -- Name: boxes; Type: TABLE; Schema: public; Owner: -
 --
@@ -47,6 +64,8 @@ CREATE TABLE public.boxes (
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
     deleted_at timestamp without time zone,
+    version integer DEFAULT 1 NOT NULL,
+    code character varying(8) NOT NULL,
     CONSTRAINT boxes_packing_mode_check CHECK (((packing_mode)::text = ANY ((ARRAY['self'::character varying, 'sort'::character varying])::text[]))),
     CONSTRAINT boxes_status_check CHECK (((status)::text = ANY ((ARRAY['in_transit'::character varying, 'pending_pack'::character varying, 'pending_pickup'::character varying, 'stored'::character varying, 'returned'::character varying, 'disposed'::character varying])::text[])))
 );
@@ -70,6 +89,76 @@ CREATE TABLE public.flyway_schema_history (
 );
 
 
+--
+-- Name: idempotency_keys; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.idempotency_keys (
+    user_id uuid NOT NULL,
+    key character varying(255) NOT NULL,
+    method character varying(10) NOT NULL,
+    path text NOT NULL,
+    request_hash character(64) NOT NULL,
+    response_status integer,
+    response_body bytea,
+    content_type character varying(100),
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    expires_at timestamp without time zone NOT NULL
+);
+
+
+--
+-- Name: item_images; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.item_images (
+    id uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    item_id uuid NOT NULL,
+    "position" integer NOT NULL,
+    caption character varying(200),
+    is_primary boolean DEFAULT false NOT NULL,
+    image_key text NOT NULL,
+    thumbnail_key text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: item_moves; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.item_moves (
+    id uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    item_id uuid NOT NULL,
+    from_box_id uuid NOT NULL,
+    to_box_id uuid NOT NULL,
+    reason character varying(10) NOT NULL,
+    actor_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT item_moves_reason_check CHECK (((reason)::text = ANY ((ARRAY['move'::character varying, 'merge'::character varying, 'split'::character varying])::text[])))
+);
+
+
+--
+-- Name: item_quantity_movements; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.item_quantity_movements (
+    id uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    item_id uuid NOT NULL,
+    delta integer NOT NULL,
+    quantity_after integer NOT NULL,
+    reason character varying(20) NOT NULL,
+    note text,
+    actor_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT item_quantity_movements_delta_check CHECK ((delta <> 0)),
+    CONSTRAINT item_quantity_movements_quantity_after_check CHECK ((quantity_after >= 0)),
+    CONSTRAINT item_quantity_movements_reason_check CHECK (((reason)::text = ANY ((ARRAY['initial'::character varying, 'added'::character varying, 'retrieved'::character varying, 'damaged'::character varying, 'correction'::character varying])::text[])))
+);
+
+
 --
 -- Name: items; Type: TABLE; Schema: public; Owner: -
 --
@@ -83,7 +172,55 @@ CREATE TABLE public.items (
     image_url text,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
-    deleted_at timestamp without time zone
+    deleted_at timestamp without time zone,
+    search_vector tsvector GENERATED ALWAYS AS ((setweight(to_tsvector('english'::regconfig, (COALESCE(name, ''::character varying))::text), 'A'::"char") || setweight(to_tsvector('english'::regconfig, COALESCE(description, ''::text)), 'B'::"char"))) STORED,
+    version integer DEFAULT 1 NOT NULL
+);
+
+
+--
+-- Name: outbox_events; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.outbox_events (
+    id uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    aggregate_type character varying(30) NOT NULL,
+    aggregate_id uuid NOT NULL,
+    event_type character varying(50) NOT NULL,
+    payload jsonb NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    next_attempt_at timestamp without time zone DEFAULT now() NOT NULL,
+    last_error text,
+    published_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: revoked_tokens; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.revoked_tokens (
+    jti character varying(255) NOT NULL,
+    expires_at timestamp without time zone NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: service_api_keys; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.service_api_keys (
+    id uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    name character varying(100) NOT NULL,
+    prefix character varying(16) NOT NULL,
+    key_hash character(64) NOT NULL,
+    scopes jsonb NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    expires_at timestamp without time zone,
+    revoked_at timestamp without time zone
 );
 
 
@@ -96,9 +233,24 @@ CREATE TABLE public.storage_locations (
     name character varying(100) NOT NULL,
     address text NOT NULL,
     capacity integer NOT NULL,
-    current_load integer NOT NULL,
+    current_load integer DEFAULT 0 NOT NULL,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
-    updated_at timestamp without time zone DEFAULT now() NOT NULL
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT storage_locations_capacity_check CHECK ((capacity > 0)),
+    CONSTRAINT storage_locations_current_load_check CHECK (((current_load >= 0) AND (current_load <= capacity)))
+);
+
+
+--
+-- Name: storage_order_items; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.storage_order_items (
+    id uuid DEFAULT public.uuid_generate_v4() NOT NULL,
+    order_id uuid NOT NULL,
+    item_id uuid NOT NULL,
+    quantity integer NOT NULL,
+    CONSTRAINT storage_order_items_quantity_check CHECK ((quantity > 0))
 );
 
 
@@ -120,6 +272,25 @@ CREATE TABLE public.storage_orders (
 );
 
 
+--
+-- Name: user_token_cutoffs; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.user_token_cutoffs (
+    user_id uuid NOT NULL,
+    revoked_before timestamp without time zone NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: box_status_events box_status_events_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.box_status_events
+    ADD CONSTRAINT box_status_events_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: boxes boxes_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -136,6 +307,38 @@ ALTER TABLE ONLY public.flyway_schema_history
     ADD CONSTRAINT flyway_schema_history_pk PRIMARY KEY (installed_rank);
 
 
+--
+-- Name: idempotency_keys idempotency_keys_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.idempotency_keys
+    ADD CONSTRAINT idempotency_keys_pkey PRIMARY KEY (user_id, key);
+
+
+--
+-- Name: item_images item_images_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_images
+    ADD CONSTRAINT item_images_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: item_moves item_moves_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_moves
+    ADD CONSTRAINT item_moves_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: item_quantity_movements item_quantity_movements_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_quantity_movements
+    ADD CONSTRAINT item_quantity_movements_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: items items_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -144,6 +347,38 @@ ALTER TABLE ONLY public.items
     ADD CONSTRAINT items_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: items items_quantity_non_negative; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE public.items
+    ADD CONSTRAINT items_quantity_non_negative CHECK ((quantity >= 0)) NOT VALID;
+
+
+--
+-- Name: outbox_events outbox_events_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.outbox_events
+    ADD CONSTRAINT outbox_events_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: revoked_tokens revoked_tokens_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.revoked_tokens
+    ADD CONSTRAINT revoked_tokens_pkey PRIMARY KEY (jti);
+
+
+--
+-- Name: service_api_keys service_api_keys_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.service_api_keys
+    ADD CONSTRAINT service_api_keys_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: storage_locations storage_locations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -152,6 +387,14 @@ ALTER TABLE ONLY public.storage_locations
     ADD CONSTRAINT storage_locations_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: storage_order_items storage_order_items_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.storage_order_items
+    ADD CONSTRAINT storage_order_items_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: storage_orders storage_orders_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -160,6 +403,14 @@ ALTER TABLE ONLY public.storage_orders
     ADD CONSTRAINT storage_orders_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: user_token_cutoffs user_token_cutoffs_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_token_cutoffs
+    ADD CONSTRAINT user_token_cutoffs_pkey PRIMARY KEY (user_id);
+
+
 --
 -- Name: flyway_schema_history_s_idx; Type: INDEX; Schema: public; Owner: -
 --
@@ -167,6 +418,134 @@ ALTER TABLE ONLY public.storage_orders
 CREATE INDEX flyway_schema_history_s_idx ON public.flyway_schema_history USING btree (success);
 
 
+--
+-- Name: idx_box_status_events_box_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_box_status_events_box_id ON public.box_status_events USING btree (box_id, created_at);
+
+
+--
+-- Name: idx_boxes_code; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_boxes_code ON public.boxes USING btree (code);
+
+
+--
+-- Name: idx_boxes_location_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_boxes_location_id ON public.boxes USING btree (location_id);
+
+
+--
+-- Name: idx_boxes_status_created_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_boxes_status_created_at ON public.boxes USING btree (status, created_at, id) WHERE (deleted_at IS NULL);
+
+
+--
+-- Name: idx_boxes_user_id_created_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_boxes_user_id_created_at ON public.boxes USING btree (user_id, created_at, id) WHERE (deleted_at IS NULL);
+
+
+--
+-- Name: idx_idempotency_keys_expires_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_idempotency_keys_expires_at ON public.idempotency_keys USING btree (expires_at);
+
+
+--
+-- Name: idx_item_images_item_id_position; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_item_images_item_id_position ON public.item_images USING btree (item_id, "position");
+
+
+--
+-- Name: idx_item_images_primary; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_item_images_primary ON public.item_images USING btree (item_id) WHERE is_primary;
+
+
+--
+-- Name: idx_item_moves_item_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_item_moves_item_id ON public.item_moves USING btree (item_id, created_at);
+
+
+--
+-- Name: idx_item_quantity_movements_item_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_item_quantity_movements_item_id ON public.item_quantity_movements USING btree (item_id, created_at);
+
+
+--
+-- Name: idx_items_search_vector; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_items_search_vector ON public.items USING gin (search_vector);
+
+
+--
+-- Name: idx_outbox_events_pending; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_outbox_events_pending ON public.outbox_events USING btree (next_attempt_at) WHERE (published_at IS NULL);
+
+
+--
+-- Name: idx_revoked_tokens_expires_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_revoked_tokens_expires_at ON public.revoked_tokens USING btree (expires_at);
+
+
+--
+-- Name: idx_service_api_keys_key_hash; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_service_api_keys_key_hash ON public.service_api_keys USING btree (key_hash);
+
+
+--
+-- Name: idx_storage_order_items_order_item; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_storage_order_items_order_item ON public.storage_order_items USING btree (order_id, item_id);
+
+
+--
+-- Name: idx_storage_orders_open_box; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_storage_orders_open_box ON public.storage_orders USING btree (box_id) WHERE ((status)::text = ANY ((ARRAY['requested'::character varying, 'in_progress'::character varying])::text[]));
+
+
+--
+-- Name: box_status_events box_status_events_box_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.box_status_events
+    ADD CONSTRAINT box_status_events_box_id_fkey FOREIGN KEY (box_id) REFERENCES public.boxes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: box_status_events box_status_events_location_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.box_status_events
+    ADD CONSTRAINT box_status_events_location_id_fkey FOREIGN KEY (location_id) REFERENCES public.storage_locations(id) ON DELETE SET NULL;
+
+
 --
 -- Name: boxes boxes_location_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -175,6 +554,46 @@ ALTER TABLE ONLY public.boxes
     ADD CONSTRAINT boxes_location_id_fkey FOREIGN KEY (location_id) REFERENCES public.storage_locations(id);
 
 
+--
+-- Name: item_images item_images_item_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_images
+    ADD CONSTRAINT item_images_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(id) ON DELETE CASCADE;
+
+
+--
+-- Name: item_moves item_moves_from_box_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_moves
+    ADD CONSTRAINT item_moves_from_box_id_fkey FOREIGN KEY (from_box_id) REFERENCES public.boxes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: item_moves item_moves_item_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_moves
+    ADD CONSTRAINT item_moves_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(id) ON DELETE CASCADE;
+
+
+--
+-- Name: item_moves item_moves_to_box_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_moves
+    ADD CONSTRAINT item_moves_to_box_id_fkey FOREIGN KEY (to_box_id) REFERENCES public.boxes(id) ON DELETE CASCADE;
+
+
+--
+-- Name: item_quantity_movements item_quantity_movements_item_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.item_quantity_movements
+    ADD CONSTRAINT item_quantity_movements_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(id) ON DELETE CASCADE;
+
+
 --
 -- Name: items items_box_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -183,6 +602,22 @@ ALTER TABLE ONLY public.items
     ADD CONSTRAINT items_box_id_fkey FOREIGN KEY (box_id) REFERENCES public.boxes(id) ON DELETE CASCADE;
 
 
+--
+-- Name: storage_order_items storage_order_items_item_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.storage_order_items
+    ADD CONSTRAINT storage_order_items_item_id_fkey FOREIGN KEY (item_id) REFERENCES public.items(id) ON DELETE CASCADE;
+
+
+--
+-- Name: storage_order_items storage_order_items_order_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.storage_order_items
+    ADD CONSTRAINT storage_order_items_order_id_fkey FOREIGN KEY (order_id) REFERENCES public.storage_orders(id) ON DELETE CASCADE;
+
+
 --
 -- Name: storage_orders storage_orders_box_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -0,0 +1,3 @@
+-- Row versions for optimistic concurrency control (ETag / If-Match)
+ALTER TABLE boxes ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE items ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,29 @@
+-- Short human-readable box codes, e.g. K7QX2MHD. The alphabet leaves out
+-- 0, O, 1 and I and must match utils.NewBoxCode.
+ALTER TABLE boxes ADD COLUMN code VARCHAR(8);
+
+CREATE FUNCTION pg_temp.random_box_code() RETURNS TEXT VOLATILE LANGUAGE sql AS $$
+    SELECT string_agg(substr('23456789ABCDEFGHJKLMNPQRSTUVWXYZ', 1 + floor(random() * 32)::int, 1), '')
+    FROM generate_series(1, 8)
+$$;
+
+-- Draw every code in one pass, then redraw the rare duplicates until none are
+-- left, instead of checking each new code against the table one row at a time.
+UPDATE boxes SET code = pg_temp.random_box_code();
+
+DO $$
+BEGIN
+    LOOP
+        UPDATE boxes SET code = pg_temp.random_box_code()
+        WHERE id IN (
+            SELECT id FROM (
+                SELECT id, row_number() OVER (PARTITION BY code ORDER BY id) AS n FROM boxes
+            ) numbered
+            WHERE n > 1
+        );
+        EXIT WHEN NOT FOUND;
+    END LOOP;
+END $$;
+
+ALTER TABLE boxes ALTER COLUMN code SET NOT NULL;
+CREATE UNIQUE INDEX idx_boxes_code ON boxes (code);
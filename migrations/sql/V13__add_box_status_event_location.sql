@@ -0,0 +1,3 @@
+-- Where a box was scanned, for status changes made by the scan endpoint
+ALTER TABLE box_status_events
+    ADD COLUMN location_id UUID REFERENCES storage_locations(id) ON DELETE SET NULL;
@@ -0,0 +1,12 @@
+-- History of items moved between boxes, including box merges and splits
+CREATE TABLE item_moves (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    from_box_id UUID NOT NULL REFERENCES boxes(id) ON DELETE CASCADE,
+    to_box_id UUID NOT NULL REFERENCES boxes(id) ON DELETE CASCADE,
+    reason VARCHAR(10) NOT NULL CHECK (reason IN ('move', 'merge', 'split')),
+    actor_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_moves_item_id ON item_moves (item_id, created_at);
@@ -0,0 +1,23 @@
+-- Ledger of item quantity changes; items.quantity is kept in sync with it
+CREATE TABLE item_quantity_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    delta INTEGER NOT NULL CHECK (delta <> 0),
+    quantity_after INTEGER NOT NULL CHECK (quantity_after >= 0),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('initial', 'added', 'retrieved', 'damaged', 'correction')),
+    note TEXT,
+    actor_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_quantity_movements_item_id ON item_quantity_movements (item_id, created_at);
+
+-- Open the ledger of existing items with their current quantity
+INSERT INTO item_quantity_movements (item_id, delta, quantity_after, reason, actor_id, created_at)
+SELECT items.id, items.quantity, items.quantity, 'initial', boxes.user_id, items.created_at
+FROM items
+JOIN boxes ON boxes.id = items.box_id
+WHERE items.quantity > 0;
+
+-- Not validated against existing rows, but enforced from now on
+ALTER TABLE items ADD CONSTRAINT items_quantity_non_negative CHECK (quantity >= 0) NOT VALID;
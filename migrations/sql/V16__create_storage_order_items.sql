@@ -0,0 +1,9 @@
+-- Items and quantities requested by a partial return order
+CREATE TABLE storage_order_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES storage_orders(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE UNIQUE INDEX idx_storage_order_items_order_item ON storage_order_items (order_id, item_id);
@@ -0,0 +1,2 @@
+-- At most one open order per box, so concurrent CreateOrder calls cannot both insert one
+CREATE UNIQUE INDEX idx_storage_orders_open_box ON storage_orders (box_id) WHERE status IN ('requested', 'in_progress');
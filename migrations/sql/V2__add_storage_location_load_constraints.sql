@@ -0,0 +1,7 @@
+-- Keep current_load within [0, capacity] for box placement
+ALTER TABLE storage_locations
+    ALTER COLUMN current_load SET DEFAULT 0,
+    ADD CONSTRAINT storage_locations_capacity_check CHECK (capacity > 0),
+    ADD CONSTRAINT storage_locations_current_load_check CHECK (current_load >= 0 AND current_load <= capacity);
+
+CREATE INDEX idx_boxes_location_id ON boxes (location_id);
@@ -0,0 +1,13 @@
+-- Audit trail of box status changes
+CREATE TABLE box_status_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    box_id UUID NOT NULL REFERENCES boxes(id) ON DELETE CASCADE,
+    from_status VARCHAR(30) NOT NULL,
+    to_status VARCHAR(30) NOT NULL,
+    actor_id UUID NOT NULL,
+    account_type VARCHAR(30) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_box_status_events_box_id ON box_status_events (box_id, created_at);
@@ -0,0 +1,2 @@
+-- Keyset pagination of GET /boxes over (created_at, id) per user
+CREATE INDEX idx_boxes_user_id_created_at ON boxes (user_id, created_at, id) WHERE deleted_at IS NULL;
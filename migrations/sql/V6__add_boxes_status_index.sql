@@ -0,0 +1,2 @@
+-- Employee work queues filter all boxes by status and creation date
+CREATE INDEX idx_boxes_status_created_at ON boxes (status, created_at, id) WHERE deleted_at IS NULL;
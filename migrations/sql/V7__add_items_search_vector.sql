@@ -0,0 +1,8 @@
+-- Full-text search over item names and descriptions
+ALTER TABLE items
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_items_search_vector ON items USING gin (search_vector);
@@ -0,0 +1,4 @@
+-- Uploaded item images live in the blob store; only their keys are kept here
+ALTER TABLE items
+    ADD COLUMN image_key TEXT,
+    ADD COLUMN thumbnail_key TEXT;
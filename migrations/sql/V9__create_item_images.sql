@@ -0,0 +1,27 @@
+-- Ordered photos of an item, replacing the single image per item
+CREATE TABLE item_images (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    item_id UUID NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    caption VARCHAR(200),
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    image_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_images_item_id_position ON item_images (item_id, position);
+
+-- At most one primary image per item
+CREATE UNIQUE INDEX idx_item_images_primary ON item_images (item_id) WHERE is_primary;
+
+-- Carry over images uploaded through POST /items/:id/image
+INSERT INTO item_images (item_id, position, is_primary, image_key, thumbnail_key)
+SELECT id, 0, TRUE, image_key, thumbnail_key
+FROM items
+WHERE image_key IS NOT NULL AND thumbnail_key IS NOT NULL;
+
+ALTER TABLE items
+    DROP COLUMN image_key,
+    DROP COLUMN thumbnail_key;
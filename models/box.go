@@ -11,12 +11,15 @@ import (
 type Box struct {
 	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	UserID      uuid.UUID  `gorm:"not null"`
-	PackingMode string     `gorm:"type:varchar(20);not null"` // 'self' or 'sort'
-	Status      string     `gorm:"type:varchar(30);not null"` // 'pending_pickup', 'stored', 'in_transit', etc.
+	Code        string     `gorm:"type:varchar(8);uniqueIndex:idx_boxes_code;not null"` // short code for people, see utils.NewBoxCode
+	PackingMode string     `gorm:"type:varchar(20);not null"`                           // 'self' or 'sort'
+	Status      string     `gorm:"type:varchar(30);not null"`                           // 'pending_pickup', 'stored', 'in_transit', etc.
 	LocationID  *uuid.UUID `gorm:"type:uuid"`
 	Location    *StorageLocation
 	Items       []Item `gorm:"foreignKey:BoxID"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
+	// Version is bumped on every write and exposed as the ETag
+	Version int `gorm:"not null;default:1"`
 }
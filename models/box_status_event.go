@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BoxStatusEvent records a single status change of a box and who made it
+type BoxStatusEvent struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	BoxID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	FromStatus  string    `gorm:"type:varchar(30);not null"`
+	ToStatus    string    `gorm:"type:varchar(30);not null"`
+	ActorID     uuid.UUID `gorm:"type:uuid;not null"`
+	AccountType string    `gorm:"type:varchar(30);not null"`
+	Note        string    `gorm:"type:text"`
+	// LocationID is where the box was scanned, for changes made by a scan
+	LocationID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time
+}
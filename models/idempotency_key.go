@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyKey remembers the outcome of a POST sent with an Idempotency-Key
+// header so that retries replay it. ResponseStatus is nil while the first
+// request is still in flight.
+type IdempotencyKey struct {
+	UserID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Key            string    `gorm:"type:varchar(255);primaryKey"`
+	Method         string    `gorm:"type:varchar(10);not null"`
+	Path           string    `gorm:"type:text;not null"`
+	RequestHash    string    `gorm:"type:char(64);not null"`
+	ResponseStatus *int
+	ResponseBody   []byte
+	ContentType    string `gorm:"type:varchar(100)"`
+	CreatedAt      time.Time
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
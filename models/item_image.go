@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemImage is one photo of an item. Images are ordered by Position and at most
+// one per item is the primary image shown in listings.
+type ItemImage struct {
+	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ItemID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	Position     int       `gorm:"not null"`
+	Caption      string    `gorm:"type:varchar(200)"`
+	IsPrimary    bool      `gorm:"not null;default:false"`
+	ImageKey     string    `gorm:"type:text;not null"`
+	ThumbnailKey string    `gorm:"type:text;not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemMove records an item being moved from one box to another and why:
+// "move", "merge" or "split".
+type ItemMove struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ItemID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	FromBoxID uuid.UUID `gorm:"type:uuid;not null"`
+	ToBoxID   uuid.UUID `gorm:"type:uuid;not null"`
+	Reason    string    `gorm:"type:varchar(10);not null"`
+	ActorID   uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time
+}
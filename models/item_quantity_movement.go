@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ItemQuantityMovement is one entry of an item's quantity ledger. Summing Delta
+// over an item's movements gives its current Quantity.
+type ItemQuantityMovement struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ItemID        uuid.UUID `gorm:"type:uuid;not null;index"`
+	Delta         int       `gorm:"not null"`
+	QuantityAfter int       `gorm:"not null"`
+	Reason        string    `gorm:"type:varchar(20);not null"` // 'initial', 'added', 'retrieved', 'damaged' or 'correction'
+	Note          string    `gorm:"type:text"`
+	ActorID       uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt     time.Time
+}
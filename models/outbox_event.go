@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change
+// that produced it, waiting to be relayed to other services
+type OutboxEvent struct {
+	ID            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	AggregateType string    `gorm:"type:varchar(30);not null"` // 'box' or 'item'
+	AggregateID   uuid.UUID `gorm:"type:uuid;not null"`
+	EventType     string    `gorm:"type:varchar(50);not null"` // 'box.created', 'item.deleted', etc.
+	Payload       string    `gorm:"type:jsonb;not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null"`
+	LastError     string    `gorm:"type:text"`
+	PublishedAt   *time.Time
+	CreatedAt     time.Time
+}
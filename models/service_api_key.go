@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAPIKey lets another service call the API without a user token. Only
+// the SHA-256 hash of the key is stored; Prefix keeps its first characters so
+// admins can tell keys apart.
+type ServiceAPIKey struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	Prefix    string    `gorm:"type:varchar(16);not null"`
+	KeyHash   string    `gorm:"type:char(64);not null;uniqueIndex"`
+	Scopes    []string  `gorm:"type:jsonb;serializer:json;not null"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
@@ -14,6 +14,8 @@ type StorageOrder struct {
 	Type          string    `gorm:"type:varchar(20);not null"` // 'pickup', 'return', 'relocate'
 	ScheduledDate time.Time `gorm:"not null"`
 	Status        string    `gorm:"type:varchar(30);not null"` // 'requested', 'in_progress', 'completed', 'cancelled'
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	// Items limits a return to these items; without any the whole box is returned
+	Items     []StorageOrderItem `gorm:"foreignKey:OrderID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
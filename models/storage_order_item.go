@@ -0,0 +1,14 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// StorageOrderItem is one line of a partial return: how many of an item the
+// customer wants back from the order's box.
+type StorageOrderItem struct {
+	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_storage_order_items_order_item"`
+	ItemID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_storage_order_items_order_item"`
+	Quantity int       `gorm:"not null"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedToken denies a single access token by its jti claim. The row is only
+// needed until the token would have expired anyway.
+type RevokedToken struct {
+	JTI       string    `gorm:"column:jti;type:varchar(255);primaryKey"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// UserTokenCutoff denies every access token of a user issued before
+// RevokedBefore, e.g. after a ban or a log out everywhere.
+type UserTokenCutoff struct {
+	UserID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RevokedBefore time.Time `gorm:"not null"`
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,200 @@
+// Package policy decides what each role may do. Handlers and services ask
+// whether a principal has a Permission instead of comparing account types, so
+// adding a role only takes an entry in rolePermissions.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Role is the kind of account a request is made by, taken from the
+// account_type claim of the access token.
+type Role string
+
+const (
+	Customer       Role = "customer"
+	WarehouseStaff Role = "warehouse_staff"
+	Driver         Role = "driver"
+	Support        Role = "support"
+	// Admin holds every permission
+	Admin Role = "admin"
+	// Employee is the account type issued before roles existed. It gets the
+	// warehouse staff permissions and location management, but none of the
+	// admin-only ones such as token:revoke and api_key:manage.
+	Employee Role = "employee"
+	// Service is the role of callers authenticated with a service API key. It
+	// holds no permissions itself; the key's scopes decide what it may do.
+	Service Role = "service"
+)
+
+// Permission is an action that goes beyond what customers may do with their
+// own boxes and orders.
+type Permission string
+
+const (
+	// BoxReadAny allows viewing any user's boxes, their items, history and labels
+	BoxReadAny Permission = "box:read_any"
+	// BoxWriteAny allows adding items to and deleting any user's boxes
+	BoxWriteAny    Permission = "box:write_any"
+	BoxScan        Permission = "box:scan"
+	BoxPlace       Permission = "box:place"
+	BoxPrintLabels Permission = "box:print_labels"
+	LocationRead   Permission = "location:read"
+	LocationManage Permission = "location:manage"
+	OrderReadAny   Permission = "order:read_any"
+	// OrderCreateAny allows scheduling orders for any user's box
+	OrderCreateAny Permission = "order:create_any"
+	OrderRelocate  Permission = "order:relocate"
+	// OrderAssign allows working on orders: starting, completing and cancelling
+	// them, and reading their pick lists
+	OrderAssign Permission = "order:assign"
+	// TokenRevoke allows revoking users' access tokens
+	TokenRevoke Permission = "token:revoke"
+	// APIKeyManage allows issuing, listing and revoking service API keys
+	APIKeyManage Permission = "api_key:manage"
+)
+
+var permissions = []Permission{
+	BoxReadAny, BoxWriteAny, BoxScan, BoxPlace, BoxPrintLabels,
+	LocationRead, LocationManage,
+	OrderReadAny, OrderCreateAny, OrderRelocate, OrderAssign,
+	TokenRevoke, APIKeyManage,
+}
+
+// IsValid reports whether p is a permission that is checked anywhere, i.e. one
+// of the constants above or a SetBoxStatus permission.
+func (p Permission) IsValid() bool {
+	if status, ok := strings.CutPrefix(string(p), "box:set_status:"); ok {
+		return status != ""
+	}
+	for _, known := range permissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// SetBoxStatus is the permission to move a box to status, e.g.
+// "box:set_status:stored". Which moves exist at all is up to the box lifecycle.
+func SetBoxStatus(status string) Permission {
+	return Permission("box:set_status:" + status)
+}
+
+var warehouseStaffPermissions = []Permission{
+	BoxReadAny, BoxWriteAny, BoxScan, BoxPlace, BoxPrintLabels,
+	SetBoxStatus("in_transit"),
+	SetBoxStatus("pending_pack"),
+	SetBoxStatus("stored"),
+	SetBoxStatus("pending_pickup"),
+	SetBoxStatus("returned"),
+	LocationRead,
+	OrderReadAny, OrderCreateAny, OrderRelocate, OrderAssign,
+}
+
+var rolePermissions = map[Role][]Permission{
+	Customer: {
+		SetBoxStatus("pending_pickup"),
+		SetBoxStatus("in_transit"),
+	},
+	WarehouseStaff: warehouseStaffPermissions,
+	Employee:       append([]Permission{LocationManage}, warehouseStaffPermissions...),
+	Driver: {
+		BoxReadAny, BoxScan,
+		SetBoxStatus("in_transit"),
+		SetBoxStatus("pending_pickup"),
+		SetBoxStatus("returned"),
+		LocationRead,
+		OrderReadAny, OrderAssign,
+	},
+	Support: {
+		BoxReadAny,
+		OrderReadAny, OrderCreateAny,
+	},
+}
+
+// ParseRole returns the role for an account_type claim. Unknown account types
+// get the customer role, which only covers the caller's own resources.
+func ParseRole(accountType string) Role {
+	role := Role(accountType)
+	if _, ok := rolePermissions[role]; ok || role == Admin {
+		return role
+	}
+	return Customer
+}
+
+// Can reports whether the role holds the permission.
+func (r Role) Can(perm Permission) bool {
+	if r == Admin {
+		return true
+	}
+	for _, p := range rolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize returns a *ForbiddenError unless the role holds the permission.
+func (r Role) Authorize(perm Permission) error {
+	if r.Can(perm) {
+		return nil
+	}
+	return &ForbiddenError{Role: r, Permission: perm}
+}
+
+// Principal is the authenticated caller of a request. For services UserID is
+// the ID of their API key and Scopes lists what the key allows.
+type Principal struct {
+	UserID uuid.UUID
+	Role   Role
+	Scopes []Permission
+}
+
+func (p Principal) Can(perm Permission) bool {
+	if p.Role == Service {
+		for _, scope := range p.Scopes {
+			if scope == perm {
+				return true
+			}
+		}
+		return false
+	}
+	return p.Role.Can(perm)
+}
+
+// Authorize returns a *ForbiddenError unless the principal holds the permission.
+func (p Principal) Authorize(perm Permission) error {
+	if p.Can(perm) {
+		return nil
+	}
+	return &ForbiddenError{Role: p.Role, Permission: perm}
+}
+
+// CanAccess reports whether the principal may act on a resource owned by
+// ownerID: owners always may, anyone else needs perm.
+func (p Principal) CanAccess(ownerID uuid.UUID, perm Permission) bool {
+	return p.UserID == ownerID || p.Can(perm)
+}
+
+// ErrForbidden matches every *ForbiddenError with errors.Is.
+var ErrForbidden = errors.New("forbidden")
+
+// ForbiddenError is returned when a role lacks the permission for an action.
+type ForbiddenError struct {
+	Role       Role
+	Permission Permission
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("permission %s required", e.Permission)
+}
+
+func (e *ForbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
@@ -0,0 +1,76 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRole(t *testing.T) {
+	assert.Equal(t, Customer, ParseRole("customer"))
+	assert.Equal(t, WarehouseStaff, ParseRole("warehouse_staff"))
+	assert.Equal(t, Admin, ParseRole("admin"))
+	assert.Equal(t, Employee, ParseRole("employee"))
+	assert.Equal(t, Customer, ParseRole(""))
+	assert.Equal(t, Customer, ParseRole("superuser"))
+}
+
+func TestRoleCan(t *testing.T) {
+	assert.True(t, Customer.Can(SetBoxStatus("pending_pickup")))
+	assert.False(t, Customer.Can(SetBoxStatus("stored")))
+	assert.False(t, Customer.Can(BoxReadAny))
+
+	assert.True(t, WarehouseStaff.Can(BoxScan))
+	assert.False(t, WarehouseStaff.Can(LocationManage))
+	assert.False(t, Support.Can(OrderAssign))
+
+	assert.True(t, Admin.Can(LocationManage))
+	assert.True(t, Admin.Can(SetBoxStatus("disposed")))
+	assert.True(t, Employee.Can(BoxPrintLabels))
+	assert.True(t, Employee.Can(LocationManage))
+	assert.False(t, Employee.Can(SetBoxStatus("disposed")))
+	assert.False(t, Employee.Can(TokenRevoke))
+	assert.False(t, Employee.Can(APIKeyManage))
+}
+
+func TestAuthorize(t *testing.T) {
+	err := Driver.Authorize(LocationManage)
+	assert.ErrorIs(t, err, ErrForbidden)
+	var forbidden *ForbiddenError
+	if assert.True(t, errors.As(err, &forbidden)) {
+		assert.Equal(t, LocationManage, forbidden.Permission)
+	}
+	assert.NoError(t, Driver.Authorize(BoxScan))
+}
+
+func TestCanAccess(t *testing.T) {
+	owner := uuid.New()
+	customer := Principal{UserID: owner, Role: Customer}
+	assert.True(t, customer.CanAccess(owner, BoxReadAny))
+	assert.False(t, customer.CanAccess(uuid.New(), BoxReadAny))
+
+	support := Principal{UserID: uuid.New(), Role: Support}
+	assert.True(t, support.CanAccess(owner, BoxReadAny))
+}
+
+func TestServicePrincipal(t *testing.T) {
+	billing := Principal{UserID: uuid.New(), Role: Service, Scopes: []Permission{OrderReadAny}}
+	assert.True(t, billing.Can(OrderReadAny))
+	assert.False(t, billing.Can(OrderAssign))
+	assert.ErrorIs(t, billing.Authorize(TokenRevoke), ErrForbidden)
+	assert.False(t, billing.CanAccess(uuid.New(), BoxReadAny))
+
+	// Services only get what their scopes list, not what the role name suggests
+	assert.False(t, Service.Can(OrderReadAny))
+	assert.Equal(t, Customer, ParseRole("service"))
+}
+
+func TestPermissionIsValid(t *testing.T) {
+	assert.True(t, BoxScan.IsValid())
+	assert.True(t, TokenRevoke.IsValid())
+	assert.True(t, SetBoxStatus("stored").IsValid())
+	assert.False(t, SetBoxStatus("").IsValid())
+	assert.False(t, Permission("box:fly").IsValid())
+}
@@ -3,14 +3,16 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/sandroJayas/storage-service/controllers"
+	"github.com/sandroJayas/storage-service/domain/repository"
 	"github.com/sandroJayas/storage-service/middleware"
+	"github.com/sandroJayas/storage-service/policy"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
 	"net/http"
 )
 
-func RegisterStorageRoutes(r *gin.Engine, boxController *controllers.BoxController, itemController *controllers.ItemController, db *gorm.DB) {
+func RegisterStorageRoutes(r *gin.Engine, boxController *controllers.BoxController, itemController *controllers.ItemController, orderController *controllers.OrderController, locationController *controllers.LocationController, blobController *controllers.BlobController, revocationController *controllers.RevocationController, apiKeyController *controllers.ServiceAPIKeyController, boxRepo repository.BoxRepository, idempotencyRepo repository.IdempotencyRepository, verifier *middleware.TokenVerifier, revocations middleware.RevocationChecker, apiKeys middleware.ServiceKeyAuthenticator, rateLimiter *middleware.RateLimiter, db *gorm.DB) {
 
 	r.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
@@ -27,26 +29,128 @@ func RegisterStorageRoutes(r *gin.Engine, boxController *controllers.BoxControll
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Runs before auth on every authenticated group, so requests with bad credentials are limited too
+	clientIPLimit := rateLimiter.ClientIP()
+	auth := middleware.AuthMiddleware(verifier, revocations, apiKeys)
+	// Applied to every authenticated group so that all POST routes honour Idempotency-Key
+	idempotency := middleware.Idempotency(idempotencyRepo)
+
+	// Signed blob downloads carry their own authorization in the query string
+	if blobController != nil {
+		r.GET("/blobs/*key", blobController.GetBlob)
+	}
+
+	// Called by other services with a service API key
+	internal := r.Group("/internal")
+	internal.Use(clientIPLimit)
+	internal.Use(auth)
+	internal.Use(rateLimiter.Group("internal"))
+	internal.Use(idempotency)
+	{
+		internal.POST("/revocations/tokens", middleware.Require(policy.TokenRevoke), revocationController.RevokeToken)
+		internal.POST("/revocations/users", middleware.Require(policy.TokenRevoke), revocationController.RevokeUserTokens)
+	}
+
 	boxes := r.Group("/boxes")
-	boxes.Use(middleware.AuthMiddleware())
-	boxes.Use(middleware.RateLimitMiddleware())
+	boxes.Use(clientIPLimit)
+	boxes.Use(auth)
+	boxes.Use(rateLimiter.Group("boxes"))
+	boxes.Use(middleware.ResolveBoxCode(boxRepo))
+	boxes.Use(idempotency)
 	{
-		boxes.POST("", boxController.CreateBox)
-		boxes.GET("", boxController.ListUserBoxes)
-		boxes.GET(":id", boxController.GetBoxByID)
-		boxes.PATCH(":id/status", boxController.UpdateStatus)
-		boxes.DELETE(":id", boxController.DeleteBox)
+		boxes.POST("scan", middleware.Require(policy.BoxScan), boxController.ScanBox)
+		boxes.PUT(":id/location", middleware.Require(policy.BoxPlace), locationController.PlaceBox)
+		boxes.DELETE(":id/location", middleware.Require(policy.BoxPlace), locationController.RemoveBox)
+	}
+
+	// Routes on the caller's own boxes, closed to service API keys
+	userBoxes := boxes.Group("")
+	userBoxes.Use(middleware.RequireUser())
+	{
+		userBoxes.POST("", boxController.CreateBox)
+		userBoxes.GET("", boxController.ListUserBoxes)
+		userBoxes.GET("export", boxController.ExportInventory)
+		userBoxes.GET(":id", boxController.GetBoxByID)
+		userBoxes.PATCH(":id/status", boxController.UpdateStatus)
+		userBoxes.GET(":id/history", boxController.GetStatusHistory)
+		userBoxes.GET(":id/label", boxController.GetBoxLabel)
+		userBoxes.DELETE(":id", boxController.DeleteBox)
 
-		boxes.POST(":id/items", itemController.AddItem)
-		boxes.GET(":id/items", itemController.ListItems)
+		userBoxes.POST(":id/items", itemController.AddItem)
+		userBoxes.POST(":id/items/bulk", itemController.ImportItems)
+		userBoxes.GET(":id/items", itemController.ListItems)
+		userBoxes.POST(":id/items/move", itemController.MoveItems)
+		userBoxes.POST(":id/merge", itemController.MergeBoxes)
+		userBoxes.POST(":id/split", itemController.SplitBox)
 	}
 
 	items := r.Group("/items")
-	items.Use(middleware.AuthMiddleware())
-	boxes.Use(middleware.RateLimitMiddleware())
+	items.Use(clientIPLimit)
+	items.Use(auth)
+	items.Use(rateLimiter.Group("items"))
+	items.Use(middleware.RequireUser())
+	items.Use(idempotency)
 	{
+		items.GET("search", itemController.SearchItems)
 		items.GET(":id", itemController.GetItem)
+		items.GET(":id/history", itemController.GetItemHistory)
+		items.POST(":id/quantity-movements", itemController.AdjustQuantity)
+		items.GET(":id/quantity-movements", itemController.ListQuantityMovements)
 		items.PATCH(":id", itemController.UpdateItemByID)
+		items.POST(":id/image", itemController.UploadImage)
+		items.POST(":id/images", itemController.AddImage)
+		items.PUT(":id/images/order", itemController.ReorderImages)
+		items.PATCH(":id/images/:image_id", itemController.UpdateImage)
+		items.DELETE(":id/images/:image_id", itemController.DeleteImage)
 		items.DELETE(":id", itemController.DeleteItem)
 	}
+
+	orders := r.Group("/orders")
+	orders.Use(clientIPLimit)
+	orders.Use(auth)
+	orders.Use(rateLimiter.Group("orders"))
+	orders.Use(idempotency)
+	{
+		orders.POST("", middleware.RequireUser(policy.OrderCreateAny), orderController.CreateOrder)
+		orders.GET("", middleware.RequireUser(policy.OrderReadAny), orderController.ListOrders)
+		orders.GET(":id", middleware.RequireUser(policy.OrderReadAny), orderController.GetOrder)
+		orders.GET(":id/pick-list", middleware.Require(policy.OrderAssign), orderController.GetPickList)
+		orders.PATCH(":id/status", middleware.RequireUser(policy.OrderAssign), orderController.UpdateOrderStatus)
+	}
+
+	locations := r.Group("/locations")
+	locations.Use(clientIPLimit)
+	locations.Use(auth)
+	locations.Use(middleware.Require(policy.LocationRead))
+	locations.Use(rateLimiter.Group("locations"))
+	locations.Use(idempotency)
+	{
+		locations.POST("", middleware.Require(policy.LocationManage), locationController.CreateLocation)
+		locations.GET("", locationController.ListLocations)
+		locations.GET(":id", locationController.GetLocation)
+		locations.PATCH(":id", middleware.Require(policy.LocationManage), locationController.UpdateLocation)
+		locations.DELETE(":id", middleware.Require(policy.LocationManage), locationController.DeleteLocation)
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(clientIPLimit)
+	admin.Use(auth)
+	admin.Use(rateLimiter.Group("admin"))
+	admin.Use(idempotency)
+	{
+		admin.GET("/boxes", middleware.Require(policy.BoxReadAny), boxController.ListAllBoxes)
+		admin.POST("/boxes/labels", middleware.Require(policy.BoxPrintLabels), boxController.PrintBoxLabels)
+	}
+
+	// No Idempotency-Key support here: a stored response would keep the plaintext of a new key
+	keys := r.Group("/admin/api-keys")
+	keys.Use(clientIPLimit)
+	keys.Use(auth)
+	keys.Use(rateLimiter.Group("admin"))
+	keys.Use(middleware.Require(policy.APIKeyManage))
+	{
+		keys.POST("", apiKeyController.CreateAPIKey)
+		keys.GET("", apiKeyController.ListAPIKeys)
+		keys.DELETE(":id", apiKeyController.RevokeAPIKey)
+	}
 }
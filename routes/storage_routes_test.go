@@ -0,0 +1,162 @@
+package routes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/config"
+	"github.com/sandroJayas/storage-service/controllers"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/middleware"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/usecase"
+	"github.com/sandroJayas/storage-service/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// recordingIdempotencyRepository keeps every record and response body it is given.
+type recordingIdempotencyRepository struct {
+	reserved []*models.IdempotencyKey
+	bodies   [][]byte
+}
+
+func (r *recordingIdempotencyRepository) Reserve(_ context.Context, record *models.IdempotencyKey) (*models.IdempotencyKey, error) {
+	r.reserved = append(r.reserved, record)
+	return nil, nil
+}
+
+func (r *recordingIdempotencyRepository) Complete(_ context.Context, _ uuid.UUID, _ string, _ int, _ string, body []byte) error {
+	r.bodies = append(r.bodies, append([]byte(nil), body...))
+	return nil
+}
+
+func (r *recordingIdempotencyRepository) Release(context.Context, uuid.UUID, string) error {
+	return nil
+}
+
+func (r *recordingIdempotencyRepository) DeleteExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+type memoryServiceAPIKeyRepository struct {
+	keys []models.ServiceAPIKey
+}
+
+func (r *memoryServiceAPIKeyRepository) Create(_ context.Context, key *models.ServiceAPIKey) error {
+	r.keys = append(r.keys, *key)
+	return nil
+}
+
+func (r *memoryServiceAPIKeyRepository) FindByHash(_ context.Context, keyHash string) (*models.ServiceAPIKey, error) {
+	for i := range r.keys {
+		if r.keys[i].KeyHash == keyHash {
+			return &r.keys[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryServiceAPIKeyRepository) FindAll(context.Context) ([]models.ServiceAPIKey, error) {
+	return r.keys, nil
+}
+
+func (r *memoryServiceAPIKeyRepository) Revoke(context.Context, uuid.UUID) (*models.ServiceAPIKey, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestCreateAPIKeyNeverStoresPlaintextKey(t *testing.T) {
+	utils.InitLogger()
+	gin.SetMode(gin.TestMode)
+	config.AppConfig = &config.EnvConfig{AppEnv: "testing", IdempotencyKeyTTL: time.Hour, IdempotencyMaxBodyBytes: 1 << 20}
+
+	verifier, err := middleware.NewTokenVerifier(middleware.TokenVerifierConfig{HMACSecret: "secret"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":      uuid.New().String(),
+		"account_type": "admin",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	idempotencyRepo := &recordingIdempotencyRepository{}
+	apiKeyController := controllers.NewServiceAPIKeyController(usecase.NewServiceAPIKeyService(&memoryServiceAPIKeyRepository{}))
+	r := gin.New()
+	RegisterStorageRoutes(r, &controllers.BoxController{}, &controllers.ItemController{}, &controllers.OrderController{}, &controllers.LocationController{}, nil, &controllers.RevocationController{}, apiKeyController, nil, idempotencyRepo, verifier, nil, nil, nil, nil)
+
+	body := []byte(`{"name": "billing", "scopes": ["order:read_any"]}`)
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Idempotency-Key", "create-billing-key")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if !assert.Equal(t, http.StatusCreated, w.Code) {
+			return
+		}
+
+		var resp struct {
+			APIKey struct {
+				Key string `json:"key"`
+			} `json:"api_key"`
+		}
+		if !assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp)) {
+			return
+		}
+		assert.NotEmpty(t, resp.APIKey.Key)
+		assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+		for _, stored := range idempotencyRepo.bodies {
+			assert.NotContains(t, string(stored), resp.APIKey.Key)
+		}
+	}
+	assert.Empty(t, idempotencyRepo.reserved)
+	assert.Empty(t, idempotencyRepo.bodies)
+}
+
+func TestServiceKeyCannotActAsCustomer(t *testing.T) {
+	utils.InitLogger()
+	gin.SetMode(gin.TestMode)
+	config.AppConfig = &config.EnvConfig{AppEnv: "testing", IdempotencyKeyTTL: time.Hour, IdempotencyMaxBodyBytes: 1 << 20}
+
+	verifier, err := middleware.NewTokenVerifier(middleware.TokenVerifierConfig{HMACSecret: "secret"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	apiKeys := usecase.NewServiceAPIKeyService(&memoryServiceAPIKeyRepository{})
+	admin := policy.Principal{UserID: uuid.New(), Role: policy.Admin}
+	created, err := apiKeys.CreateKey(context.Background(), admin, dto.CreateServiceAPIKeyRequest{Name: "no-scopes"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The controllers have no services, so a request that got past the guard would panic
+	r := gin.New()
+	RegisterStorageRoutes(r, &controllers.BoxController{}, &controllers.ItemController{}, &controllers.OrderController{}, &controllers.LocationController{}, nil, &controllers.RevocationController{}, controllers.NewServiceAPIKeyController(apiKeys), nil, &recordingIdempotencyRepository{}, verifier, nil, apiKeys, nil, nil)
+
+	requests := []struct{ method, path, body string }{
+		{http.MethodPost, "/boxes", `{"packing_mode": "self"}`},
+		{http.MethodGet, "/boxes", ""},
+		{http.MethodGet, "/items/search?q=shoes", ""},
+		{http.MethodPost, "/orders", `{}`},
+	}
+	for _, req := range requests {
+		httpReq := httptest.NewRequest(req.method, req.path, bytes.NewReader([]byte(req.body)))
+		httpReq.Header.Set("X-API-Key", created.Key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httpReq)
+		assert.Equal(t, http.StatusForbidden, w.Code, req.method+" "+req.path)
+	}
+}
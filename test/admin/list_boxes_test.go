@@ -0,0 +1,85 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const adminBaseURL = "http://localhost:8080/admin"
+const boxBaseURL = "http://localhost:8080/boxes"
+
+func getJSON(t *testing.T, token, target string) (int, map[string]interface{}) {
+	req, _ := http.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func TestAdminListBoxes(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "adminlist123"
+	token := test.RegisterAndLogin(t, "adminlist+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "adminlistemp+"+timestamp+"@test.com", password)
+
+	start := time.Now().Add(-time.Minute).UTC()
+	boxID := test.CreateSortPackedBox(t, token)
+	test.CreateSortPackedBox(t, token)
+
+	_, res := getJSON(t, token, boxBaseURL+"/"+boxID)
+	userID := res["box"].(map[string]interface{})["user_id"].(string)
+
+	t.Run("customer is forbidden", func(t *testing.T) {
+		status, _ := getJSON(t, token, adminBaseURL+"/boxes")
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("employee filters by user", func(t *testing.T) {
+		status, res := getJSON(t, tokenEmployee, adminBaseURL+"/boxes?user_id="+userID)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(2), res["total"])
+		for _, b := range res["boxes"].([]interface{}) {
+			assert.Equal(t, userID, b.(map[string]interface{})["user_id"])
+		}
+	})
+
+	t.Run("employee filters by created date range", func(t *testing.T) {
+		query := url.Values{}
+		query.Set("user_id", userID)
+		query.Set("status", "in_transit")
+		query.Set("created_from", start.Format(time.RFC3339))
+		query.Set("created_to", time.Now().Add(time.Minute).UTC().Format(time.RFC3339))
+		status, res := getJSON(t, tokenEmployee, adminBaseURL+"/boxes?"+query.Encode())
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(2), res["total"])
+
+		query.Set("created_from", time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+		query.Set("created_to", time.Now().Add(2*time.Hour).UTC().Format(time.RFC3339))
+		status, res = getJSON(t, tokenEmployee, adminBaseURL+"/boxes?"+query.Encode())
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(0), res["total"])
+	})
+
+	t.Run("inverted date range", func(t *testing.T) {
+		query := url.Values{}
+		query.Set("created_from", time.Now().UTC().Format(time.RFC3339))
+		query.Set("created_to", start.Format(time.RFC3339))
+		status, _ := getJSON(t, tokenEmployee, adminBaseURL+"/boxes?"+query.Encode())
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("invalid user_id", func(t *testing.T) {
+		status, _ := getJSON(t, tokenEmployee, adminBaseURL+"/boxes?user_id=nope")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,71 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoxCodes(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "strongpass123"
+	token := test.RegisterAndLogin(t, "code+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "code-other+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+
+	getBox := func(token, id string) (*http.Response, map[string]map[string]interface{}) {
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/"+id, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		var res map[string]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp, res
+	}
+
+	resp, res := getBox(token, boxID)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	code, _ := res["box"]["code"].(string)
+	assert.Len(t, code, 8)
+
+	t.Run("code works in place of the UUID", func(t *testing.T) {
+		resp, res := getBox(token, code)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, boxID, res["box"]["id"])
+	})
+
+	t.Run("code is case-insensitive and may contain a hyphen", func(t *testing.T) {
+		resp, res := getBox(token, strings.ToLower(code[:4])+"-"+strings.ToLower(code[4:]))
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, boxID, res["box"]["id"])
+	})
+
+	t.Run("nested routes accept the code", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/"+code+"/history", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		resp, _ := getBox(token, "ZZZZZZZZ")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("other user's code", func(t *testing.T) {
+		resp, _ := getBox(otherToken, code)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("neither UUID nor code", func(t *testing.T) {
+		resp, _ := getBox(token, "not-a-box")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoxETags(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "etagpass123"
+	token := test.RegisterAndLogin(t, "boxetag+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "boxetagadmin+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+
+	updateStatus := func(ifMatch, status string) *http.Response {
+		body, _ := json.Marshal(map[string]string{"status": status})
+		req, _ := http.NewRequest(http.MethodPatch, boxBaseURL+"/"+boxID+"/status", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tokenEmployee)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", ifMatch)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	var etag string
+
+	t.Run("get returns the version as ETag", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/"+boxID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		etag = resp.Header.Get("ETag")
+		assert.Equal(t, `"1"`, etag)
+	})
+
+	t.Run("status update with current ETag", func(t *testing.T) {
+		resp := updateStatus(etag, "pending_pack")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"2"`, resp.Header.Get("ETag"))
+	})
+
+	t.Run("status update with stale ETag", func(t *testing.T) {
+		resp := updateStatus(etag, "stored")
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	})
+
+	t.Run("unparseable If-Match never matches", func(t *testing.T) {
+		resp := updateStatus(`"abc"`, "stored")
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	})
+
+	t.Run("delete with stale ETag", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, boxBaseURL+"/"+boxID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("If-Match", etag)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	})
+
+	t.Run("delete with current ETag", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, boxBaseURL+"/"+boxID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("If-Match", `"2"`)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
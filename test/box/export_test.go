@@ -0,0 +1,94 @@
+package test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportInventory(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	token := test.RegisterAndLogin(t, "export+"+timestamp+"@test.com", "strongpass123")
+
+	body, _ := json.Marshal(map[string]string{"packing_mode": "self", "item_name": "=Lamp", "item_note": "Desk lamp"})
+	req, _ := http.NewRequest(http.MethodPost, boxBaseURL, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	sortBoxID := test.CreateSortPackedBox(t, token)
+
+	export := func(format string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/export?format="+format, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("csv - one row per item, empty boxes included", func(t *testing.T) {
+		resp := export("csv")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), ".csv")
+
+		records, err := csv.NewReader(resp.Body).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 3)
+		assert.Equal(t, "box_id", records[0][0])
+		assert.Equal(t, "'=Lamp", records[1][7])
+		assert.Equal(t, sortBoxID, records[2][0])
+		assert.Empty(t, records[2][6])
+	})
+
+	t.Run("json - items nested under boxes", func(t *testing.T) {
+		resp := export("json")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res struct {
+			GeneratedAt string `json:"generated_at"`
+			Boxes       []struct {
+				ID    string                   `json:"id"`
+				Items []map[string]interface{} `json:"items"`
+			} `json:"boxes"`
+		}
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+		assert.NotEmpty(t, res.GeneratedAt)
+		assert.Len(t, res.Boxes, 2)
+		assert.Len(t, res.Boxes[0].Items, 1)
+		assert.Equal(t, "=Lamp", res.Boxes[0].Items[0]["name"])
+		assert.Empty(t, res.Boxes[1].Items)
+	})
+
+	t.Run("pdf - printable manifest", func(t *testing.T) {
+		resp := export("pdf")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+
+		data, _ := io.ReadAll(resp.Body)
+		assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+		assert.Contains(t, string(data), "%%EOF")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		resp := export("xlsx")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unauthorized - no token", func(t *testing.T) {
+		resp, err := http.Get(boxBaseURL + "/export")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusHistory(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "historypass123"
+	token := test.RegisterAndLogin(t, "history+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "historyadmin+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+
+	t.Run("setup - store the box", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{
+			"status": "stored",
+			"note":   "Shelf A3",
+		})
+		req, _ := http.NewRequest(http.MethodPatch, boxBaseURL+"/"+boxID+"/status", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tokenEmployee)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("history lists the status change", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/"+boxID+"/history", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res map[string][]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+
+		history := res["history"]
+		assert.Equal(t, 1, len(history))
+		assert.Equal(t, "in_transit", history[0]["from_status"])
+		assert.Equal(t, "stored", history[0]["to_status"])
+		assert.Equal(t, "employee", history[0]["account_type"])
+		assert.Equal(t, "Shelf A3", history[0]["note"])
+		assert.NotEmpty(t, history[0]["created_at"])
+	})
+
+	t.Run("foreign user cannot read history", func(t *testing.T) {
+		foreignToken := test.RegisterAndLogin(t, "historyB+"+timestamp+"@test.com", password)
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/"+boxID+"/history", nil)
+		req.Header.Set("Authorization", "Bearer "+foreignToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBoxIdempotency(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "idempotentpass123"
+	token := test.RegisterAndLogin(t, "idempotent+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "idempotent-other+"+timestamp+"@test.com", password)
+
+	key := uuid.NewString()
+	payload := map[string]string{"packing_mode": "self", "item_name": "Tent", "item_note": "4 person"}
+
+	createBox := func(token, key string, payload map[string]string) (*http.Response, map[string]string) {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest(http.MethodPost, boxBaseURL, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+
+		var res map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp, res
+	}
+
+	var boxID string
+
+	t.Run("first request creates the box", func(t *testing.T) {
+		resp, res := createBox(token, key, payload)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+		boxID = res["id"]
+		assert.NotEmpty(t, boxID)
+	})
+
+	t.Run("retry replays the original response", func(t *testing.T) {
+		resp, res := createBox(token, key, payload)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+		assert.Equal(t, boxID, res["id"])
+
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		listResp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		var list map[string]interface{}
+		_ = json.NewDecoder(listResp.Body).Decode(&list)
+		assert.Equal(t, float64(1), list["total"])
+	})
+
+	t.Run("same key with a different payload", func(t *testing.T) {
+		resp, _ := createBox(token, key, map[string]string{"packing_mode": "sort"})
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("keys are scoped per user", func(t *testing.T) {
+		resp, res := createBox(otherToken, key, payload)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.NotEqual(t, boxID, res["id"])
+	})
+
+	t.Run("client errors are replayed too", func(t *testing.T) {
+		badKey := uuid.NewString()
+		resp, _ := createBox(token, badKey, map[string]string{"packing_mode": "invalid"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, _ = createBox(token, badKey, map[string]string{"packing_mode": "invalid"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	})
+}
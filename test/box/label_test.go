@@ -0,0 +1,103 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoxLabels(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "strongpass123"
+	token := test.RegisterAndLogin(t, "label+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "label-other+"+timestamp+"@test.com", password)
+	employeeToken := test.RegisterAndLoginEmployee(t, "label-emp+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+	secondBoxID := test.CreateSortPackedBox(t, token)
+
+	getLabel := func(token, query string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/"+boxID+"/label"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	printLabels := func(token string, ids []string) *http.Response {
+		body, _ := json.Marshal(map[string]interface{}{"box_ids": ids})
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/admin/boxes/labels", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("png label by default", func(t *testing.T) {
+		resp := getLabel(token, "")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+		data, _ := io.ReadAll(resp.Body)
+		assert.True(t, bytes.HasPrefix(data, []byte("\x89PNG")))
+	})
+
+	t.Run("pdf label", func(t *testing.T) {
+		resp := getLabel(token, "?format=pdf")
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		data, _ := io.ReadAll(resp.Body)
+		assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		resp := getLabel(token, "?format=svg")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("other user's box", func(t *testing.T) {
+		resp := getLabel(otherToken, "")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("employee can get any label", func(t *testing.T) {
+		resp := getLabel(employeeToken, "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("batch - employee prints several labels", func(t *testing.T) {
+		resp := printLabels(employeeToken, []string{boxID, secondBoxID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("batch - unknown box", func(t *testing.T) {
+		missing := uuid.New().String()
+		resp := printLabels(employeeToken, []string{boxID, missing})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var res map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Equal(t, []interface{}{missing}, res["missing"])
+	})
+
+	t.Run("batch - empty list", func(t *testing.T) {
+		resp := printLabels(employeeToken, []string{})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("batch - customers are forbidden", func(t *testing.T) {
+		resp := printLabels(token, []string{boxID})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listBoxes(t *testing.T, token, query string) (int, map[string]interface{}) {
+	req, _ := http.NewRequest(http.MethodGet, boxBaseURL+query, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func TestListUserBoxesPagination(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	token := test.RegisterAndLogin(t, "page+"+timestamp+"@test.com", "pagepass123")
+
+	var created []string
+	for i := 0; i < 5; i++ {
+		created = append(created, test.CreateSortPackedBox(t, token))
+	}
+
+	t.Run("pages through all boxes newest first", func(t *testing.T) {
+		var seen []string
+		cursor := ""
+		for page := 0; page < 5; page++ {
+			query := "?limit=2"
+			if cursor != "" {
+				query += "&cursor=" + cursor
+			}
+			status, res := listBoxes(t, token, query)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, float64(5), res["total"])
+
+			for _, b := range res["boxes"].([]interface{}) {
+				seen = append(seen, b.(map[string]interface{})["id"].(string))
+			}
+			next, ok := res["next_cursor"].(string)
+			if !ok || next == "" {
+				break
+			}
+			cursor = next
+		}
+
+		assert.Equal(t, 5, len(seen))
+		assert.Equal(t, created[4], seen[0])
+		assert.Equal(t, created[0], seen[4])
+	})
+
+	t.Run("ascending sort", func(t *testing.T) {
+		status, res := listBoxes(t, token, "?sort=created_at&limit=1")
+		assert.Equal(t, http.StatusOK, status)
+		boxes := res["boxes"].([]interface{})
+		assert.Equal(t, created[0], boxes[0].(map[string]interface{})["id"])
+		assert.NotEmpty(t, res["next_cursor"])
+	})
+
+	t.Run("items are not included by default", func(t *testing.T) {
+		status, res := listBoxes(t, token, "")
+		assert.Equal(t, http.StatusOK, status)
+		for _, b := range res["boxes"].([]interface{}) {
+			assert.Nil(t, b.(map[string]interface{})["items"])
+		}
+	})
+
+	t.Run("filter by status and packing mode", func(t *testing.T) {
+		status, res := listBoxes(t, token, "?status=stored")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(0), res["total"])
+		assert.Empty(t, res["boxes"])
+
+		status, res = listBoxes(t, token, "?packing_mode=sort&status=in_transit")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(5), res["total"])
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		status, _ := listBoxes(t, token, "?cursor=not-a-cursor")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = listBoxes(t, token, "?limit=1000")
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = listBoxes(t, token, "?status=lost")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
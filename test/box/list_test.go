@@ -51,7 +51,7 @@ func TestListUserBoxes(t *testing.T) {
 	})
 
 	t.Run("list - should return all user boxes with correct fields", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet, boxBaseURL, nil)
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"?include=items", nil)
 		req.Header.Set("Authorization", "Bearer "+token)
 
 		resp, err := http.DefaultClient.Do(req)
@@ -46,6 +46,32 @@ func TestUpdateStatus(t *testing.T) {
 		assert.NotEmpty(t, boxID)
 	})
 
+	t.Run("update - stored- forbidden status for non-employee", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{
+			"status": "stored",
+		})
+		req, _ := http.NewRequest(http.MethodPatch, boxBaseURL+"/"+boxID+"/status", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("update - stored status for employee", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{
+			"status": "stored",
+		})
+		req, _ := http.NewRequest(http.MethodPatch, boxBaseURL+"/"+boxID+"/status", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+tokenEmployee)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
 	t.Run("update - valid status change", func(t *testing.T) {
 		body, _ := json.Marshal(map[string]string{
 			"status": "pending_pickup",
@@ -85,22 +111,9 @@ func TestUpdateStatus(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 	})
 
-	t.Run("update - stored- forbidden status for non-employee", func(t *testing.T) {
-		body, _ := json.Marshal(map[string]string{
-			"status": "stored",
-		})
-		req, _ := http.NewRequest(http.MethodPatch, boxBaseURL+"/"+boxID+"/status", bytes.NewReader(body))
-		req.Header.Set("Authorization", "Bearer "+token)
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
-	})
-
-	t.Run("update - stored status for employee", func(t *testing.T) {
+	t.Run("update - returned status for employee", func(t *testing.T) {
 		body, _ := json.Marshal(map[string]string{
-			"status": "stored",
+			"status": "returned",
 		})
 		req, _ := http.NewRequest(http.MethodPatch, boxBaseURL+"/"+boxID+"/status", bytes.NewReader(body))
 		req.Header.Set("Authorization", "Bearer "+tokenEmployee)
@@ -111,9 +124,9 @@ func TestUpdateStatus(t *testing.T) {
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
-	t.Run("update - returned status for employee", func(t *testing.T) {
+	t.Run("update - transition not allowed returns allowed statuses", func(t *testing.T) {
 		body, _ := json.Marshal(map[string]string{
-			"status": "returned",
+			"status": "disposed",
 		})
 		req, _ := http.NewRequest(http.MethodPatch, boxBaseURL+"/"+boxID+"/status", bytes.NewReader(body))
 		req.Header.Set("Authorization", "Bearer "+tokenEmployee)
@@ -121,7 +134,11 @@ func TestUpdateStatus(t *testing.T) {
 
 		resp, err := http.DefaultClient.Do(req)
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var res map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Equal(t, []interface{}{"in_transit"}, res["allowed"])
 	})
 
 	t.Run("update - invalid status", func(t *testing.T) {
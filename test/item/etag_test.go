@@ -0,0 +1,85 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemETags(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "etagpass123"
+	token := test.RegisterAndLogin(t, "itemetag+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+	itemID := test.AddItemToBox(t, token, boxID, map[string]interface{}{
+		"name":     "Bookshelf",
+		"quantity": 1,
+	})["id"]
+
+	patchItem := func(ifMatch string, fields map[string]interface{}) *http.Response {
+		body, _ := json.Marshal(fields)
+		req, _ := http.NewRequest(http.MethodPatch, itemBaseURL+itemID, bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	var etag string
+
+	t.Run("get returns the version as ETag", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, itemBaseURL+itemID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		etag = resp.Header.Get("ETag")
+		assert.Equal(t, `"1"`, etag)
+
+		var item map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&item)
+		assert.Equal(t, float64(1), item["version"])
+	})
+
+	t.Run("tablet updates with current ETag", func(t *testing.T) {
+		resp := patchItem(etag, map[string]interface{}{"quantity": 2})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"2"`, resp.Header.Get("ETag"))
+	})
+
+	t.Run("app update with the same, now stale, ETag is rejected", func(t *testing.T) {
+		resp := patchItem(etag, map[string]interface{}{"name": "Tall bookshelf"})
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+		_, item := sendItemImageJSON(t, http.MethodGet, itemBaseURL+itemID, token, nil)
+		assert.Equal(t, "Bookshelf", item["name"])
+		assert.Equal(t, float64(2), item["quantity"])
+	})
+
+	t.Run("weak ETag and wildcard are accepted", func(t *testing.T) {
+		resp := patchItem(`W/"2"`, map[string]interface{}{"name": "Tall bookshelf"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = patchItem("*", map[string]interface{}{"quantity": 3})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"4"`, resp.Header.Get("ETag"))
+	})
+
+	t.Run("delete with stale ETag", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, itemBaseURL+itemID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("If-Match", etag)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	})
+}
@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func importItems(t *testing.T, token, boxID, contentType string, body io.Reader) (int, map[string]interface{}) {
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/boxes/"+boxID+"/items/bulk", body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func countBoxItems(t *testing.T, token, boxID string) int {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/boxes/"+boxID+"/items", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string][]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return len(res["items"])
+}
+
+func TestImportItems(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "importpass123"
+	token := test.RegisterAndLogin(t, "import+"+timestamp+"@test.com", password)
+	boxID := test.CreateSortPackedBox(t, token)
+
+	t.Run("json array", func(t *testing.T) {
+		body, _ := json.Marshal([]map[string]interface{}{
+			{"name": "Plates", "description": "Set of 6", "quantity": 6},
+			{"name": "Cups", "quantity": 4},
+		})
+		status, res := importItems(t, token, boxID, "application/json", bytes.NewReader(body))
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, float64(2), res["count"])
+		assert.Len(t, res["ids"], 2)
+		assert.Equal(t, 2, countBoxItems(t, token, boxID))
+	})
+
+	t.Run("csv body", func(t *testing.T) {
+		csv := "name,description,quantity,image_url\n" +
+			"Kettle,Electric,1,\n" +
+			"\"Pans, cast iron\",,3,https://example.com/pans.jpg\n"
+		status, res := importItems(t, token, boxID, "text/csv", strings.NewReader(csv))
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, float64(2), res["count"])
+		assert.Equal(t, 4, countBoxItems(t, token, boxID))
+	})
+
+	t.Run("csv file upload", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "items.csv")
+		_, _ = part.Write([]byte("name,quantity\nToaster,1\n"))
+		_ = writer.Close()
+
+		status, res := importItems(t, token, boxID, writer.FormDataContentType(), &body)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, float64(1), res["count"])
+	})
+
+	t.Run("invalid rows reject the whole import", func(t *testing.T) {
+		csv := "name,quantity\n" +
+			"Bowls,2\n" +
+			",1\n" +
+			"Forks,many\n"
+		status, res := importItems(t, token, boxID, "text/csv", strings.NewReader(csv))
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		rows := res["rows"].([]interface{})
+		assert.Len(t, rows, 2)
+		first := rows[0].(map[string]interface{})
+		assert.Equal(t, float64(2), first["row"])
+		assert.Equal(t, []interface{}{"name is required"}, first["errors"])
+		second := rows[1].(map[string]interface{})
+		assert.Equal(t, float64(3), second["row"])
+		assert.Equal(t, []interface{}{"quantity must be a whole number"}, second["errors"])
+
+		assert.Equal(t, 5, countBoxItems(t, token, boxID))
+	})
+
+	t.Run("self-packed boxes are rejected", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"packing_mode": "self", "item_name": "Bike", "item_note": "Red"})
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/boxes", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		var created map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&created)
+
+		items, _ := json.Marshal([]map[string]interface{}{{"name": "Helmet", "quantity": 1}})
+		status, _ := importItems(t, token, created["id"], "application/json", bytes.NewReader(items))
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unsupported content type", func(t *testing.T) {
+		status, _ := importItems(t, token, boxID, "application/xml", strings.NewReader("<items/>"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, status)
+	})
+}
@@ -0,0 +1,161 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"mime/multipart"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const itemBaseURL = "http://localhost:8080/items/"
+
+func addItemImage(t *testing.T, token, itemID, caption string, primary bool) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("image", "photo.png")
+	_, _ = part.Write(testPNG(40, 30))
+	_ = writer.WriteField("caption", caption)
+	if primary {
+		_ = writer.WriteField("primary", "true")
+	}
+	_ = writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, itemBaseURL+itemID+"/images", &body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func sendItemImageJSON(t *testing.T, method, url, token string, payload interface{}) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	if payload != nil {
+		_ = json.NewEncoder(&body).Encode(payload)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func getItemImages(t *testing.T, token, itemID string) map[string]interface{} {
+	status, item := sendItemImageJSON(t, http.MethodGet, itemBaseURL+itemID, token, nil)
+	assert.Equal(t, http.StatusOK, status)
+	return item
+}
+
+func TestItemImages(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "imagespass123"
+	token := test.RegisterAndLogin(t, "images+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "images-other+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+	itemID := test.AddItemToBox(t, token, boxID, map[string]interface{}{
+		"name":     "Armchair",
+		"quantity": 1,
+	})["id"]
+
+	var frontID, sideID, damageID string
+
+	t.Run("first image becomes primary", func(t *testing.T) {
+		status, res := addItemImage(t, token, itemID, "Front", false)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, true, res["primary"])
+		assert.Equal(t, float64(0), res["position"])
+		assert.Equal(t, "Front", res["caption"])
+		frontID = res["id"].(string)
+
+		_, res = addItemImage(t, token, itemID, "Side", false)
+		assert.Equal(t, false, res["primary"])
+		assert.Equal(t, float64(1), res["position"])
+		sideID = res["id"].(string)
+	})
+
+	t.Run("adding a primary image demotes the previous one", func(t *testing.T) {
+		status, res := addItemImage(t, token, itemID, "Scratch on left arm", true)
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, true, res["primary"])
+		damageID = res["id"].(string)
+
+		item := getItemImages(t, token, itemID)
+		images := item["images"].([]interface{})
+		assert.Len(t, images, 3)
+		for _, raw := range images {
+			image := raw.(map[string]interface{})
+			assert.Equal(t, image["id"] == damageID, image["primary"])
+			if image["primary"] == true {
+				assert.Equal(t, image["url"], item["image_url"])
+			}
+		}
+	})
+
+	t.Run("update caption and primary", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodPatch, itemBaseURL+itemID+"/images/"+frontID, token, map[string]interface{}{
+			"caption": "Front, good condition",
+			"primary": true,
+		})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "Front, good condition", res["caption"])
+		assert.Equal(t, true, res["primary"])
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodPut, itemBaseURL+itemID+"/images/order", token, map[string]interface{}{
+			"image_ids": []string{damageID, sideID, frontID},
+		})
+		assert.Equal(t, http.StatusOK, status)
+		images := res["images"].([]interface{})
+		assert.Equal(t, damageID, images[0].(map[string]interface{})["id"])
+		assert.Equal(t, frontID, images[2].(map[string]interface{})["id"])
+	})
+
+	t.Run("reorder must list every image once", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPut, itemBaseURL+itemID+"/images/order", token, map[string]interface{}{
+			"image_ids": []string{damageID, sideID},
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("deleting the primary image promotes the next one", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodDelete, itemBaseURL+itemID+"/images/"+frontID, token, nil)
+		assert.Equal(t, http.StatusOK, status)
+
+		item := getItemImages(t, token, itemID)
+		images := item["images"].([]interface{})
+		assert.Len(t, images, 2)
+		first := images[0].(map[string]interface{})
+		assert.Equal(t, damageID, first["id"])
+		assert.Equal(t, true, first["primary"])
+		assert.Equal(t, first["url"], item["image_url"])
+	})
+
+	t.Run("unknown image", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodDelete, itemBaseURL+itemID+"/images/"+frontID, token, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("other user cannot manage images", func(t *testing.T) {
+		status, _ := addItemImage(t, otherToken, itemID, "Mine now", false)
+		assert.Equal(t, http.StatusNotFound, status)
+
+		status, _ = sendItemImageJSON(t, http.MethodDelete, itemBaseURL+itemID+"/images/"+sideID, otherToken, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
@@ -85,3 +85,79 @@ func TestListItems(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 }
+
+func TestStaffManageItemsInCustomerBox(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "staffitemspass123"
+	customerToken := test.RegisterAndLogin(t, "staffitemsowner+"+timestamp+"@test.com", password)
+	employeeToken := test.RegisterAndLoginEmployee(t, "staffitemsemp+"+timestamp+"@test.com", password)
+
+	sortBoxID := test.CreateSortPackedBox(t, customerToken)
+
+	t.Run("employee adds an item to the customer's box", func(t *testing.T) {
+		test.AddItemToBox(t, employeeToken, sortBoxID, map[string]interface{}{
+			"name":     "Desk lamp",
+			"quantity": 1,
+		})
+	})
+
+	t.Run("employee lists the customer's items", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, boxBaseURL+"/"+sortBoxID+"/items", nil)
+		req.Header.Set("Authorization", "Bearer "+employeeToken)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res map[string][]map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		assert.NoError(t, err)
+		if assert.Len(t, res["items"], 1) {
+			assert.Equal(t, "Desk lamp", res["items"][0]["name"])
+		}
+	})
+}
+
+func TestStaffAdjustItemQuantityInCustomerBox(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "staffledgerpass123"
+	customerToken := test.RegisterAndLogin(t, "staffledgerowner+"+timestamp+"@test.com", password)
+	employeeToken := test.RegisterAndLoginEmployee(t, "staffledgeremp+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "staffledgerother+"+timestamp+"@test.com", password)
+
+	sortBoxID := test.CreateSortPackedBox(t, customerToken)
+	item := test.AddItemToBox(t, customerToken, sortBoxID, map[string]interface{}{"name": "Plates", "quantity": 6})
+	ledgerURL := itemBaseURL + item["id"] + "/quantity-movements"
+
+	t.Run("employee records a damaged quantity", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodPost, ledgerURL, employeeToken, map[string]interface{}{
+			"delta":  -1,
+			"reason": "damaged",
+			"note":   "Chipped on intake",
+		})
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, float64(5), res["quantity"])
+	})
+
+	t.Run("employee reads the ledger with themselves as actor", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodGet, ledgerURL, employeeToken, nil)
+		assert.Equal(t, http.StatusOK, status)
+		movements := res["movements"].([]interface{})
+		if !assert.Len(t, movements, 2) {
+			return
+		}
+		initial := movements[0].(map[string]interface{})
+		damaged := movements[1].(map[string]interface{})
+		assert.Equal(t, "damaged", damaged["reason"])
+		assert.NotEqual(t, initial["actor_id"], damaged["actor_id"])
+	})
+
+	t.Run("other customers still cannot adjust or read it", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, ledgerURL, otherToken, map[string]interface{}{
+			"delta":  -1,
+			"reason": "retrieved",
+		})
+		assert.Equal(t, http.StatusNotFound, status)
+		status, _ = sendItemImageJSON(t, http.MethodGet, ledgerURL, otherToken, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
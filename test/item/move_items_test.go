@@ -0,0 +1,106 @@
+package test
+
+import (
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveMergeSplit(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "movepass123"
+	token := test.RegisterAndLogin(t, "move+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "move-other+"+timestamp+"@test.com", password)
+
+	firstBox := test.CreateSortPackedBox(t, token)
+	secondBox := test.CreateSortPackedBox(t, token)
+	otherUsersBox := test.CreateSortPackedBox(t, otherToken)
+
+	var itemIDs []string
+	for _, name := range []string{"Lamp", "Rug", "Vase"} {
+		item := test.AddItemToBox(t, token, firstBox, map[string]interface{}{"name": name, "quantity": 1})
+		itemIDs = append(itemIDs, item["id"])
+	}
+
+	t.Run("move items to another box", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, boxBaseURL+"/"+firstBox+"/items/move", token, map[string]interface{}{
+			"item_ids":   itemIDs[:2],
+			"target_box": secondBox,
+		})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 1, countBoxItems(t, token, firstBox))
+		assert.Equal(t, 2, countBoxItems(t, token, secondBox))
+	})
+
+	t.Run("item history records the move", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodGet, itemBaseURL+itemIDs[0]+"/history", token, nil)
+		assert.Equal(t, http.StatusOK, status)
+		history := res["history"].([]interface{})
+		assert.Len(t, history, 1)
+		move := history[0].(map[string]interface{})
+		assert.Equal(t, firstBox, move["from_box_id"])
+		assert.Equal(t, secondBox, move["to_box_id"])
+		assert.Equal(t, "move", move["reason"])
+	})
+
+	t.Run("items not in the box are rejected and nothing moves", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, boxBaseURL+"/"+firstBox+"/items/move", token, map[string]interface{}{
+			"item_ids":   []string{itemIDs[2], itemIDs[0]},
+			"target_box": secondBox,
+		})
+		assert.Equal(t, http.StatusConflict, status)
+		assert.Equal(t, 1, countBoxItems(t, token, firstBox))
+	})
+
+	t.Run("cannot move into another user's box", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, boxBaseURL+"/"+firstBox+"/items/move", token, map[string]interface{}{
+			"item_ids":   []string{itemIDs[2]},
+			"target_box": otherUsersBox,
+		})
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("cannot move into the same box", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, boxBaseURL+"/"+firstBox+"/items/move", token, map[string]interface{}{
+			"item_ids":   []string{itemIDs[2]},
+			"target_box": firstBox,
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("split items into a new box", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodPost, boxBaseURL+"/"+secondBox+"/split", token, map[string]interface{}{
+			"item_ids": []string{itemIDs[1]},
+		})
+		assert.Equal(t, http.StatusCreated, status)
+		newBox, _ := res["id"].(string)
+		assert.NotEmpty(t, res["code"])
+		assert.Equal(t, 1, countBoxItems(t, token, newBox))
+		assert.Equal(t, 1, countBoxItems(t, token, secondBox))
+	})
+
+	t.Run("merge empties and deletes the source box", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, boxBaseURL+"/"+firstBox+"/merge", token, map[string]interface{}{
+			"source_box": secondBox,
+		})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2, countBoxItems(t, token, firstBox))
+
+		status, _ = sendItemImageJSON(t, http.MethodGet, boxBaseURL+"/"+secondBox, token, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+
+		_, res := sendItemImageJSON(t, http.MethodGet, itemBaseURL+itemIDs[0]+"/history", token, nil)
+		history := res["history"].([]interface{})
+		assert.Len(t, history, 2)
+		assert.Equal(t, "merge", history[1].(map[string]interface{})["reason"])
+	})
+
+	t.Run("unknown item history", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodGet, itemBaseURL+uuid.New().String()+"/history", token, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityMovements(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "ledgerpass123"
+	token := test.RegisterAndLogin(t, "ledger+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "ledger-other+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+	item := test.AddItemToBox(t, token, boxID, map[string]interface{}{"name": "Candles", "quantity": 5})
+	ledgerURL := itemBaseURL + item["id"] + "/quantity-movements"
+
+	t.Run("ledger starts with the initial quantity", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodGet, ledgerURL, token, nil)
+		assert.Equal(t, http.StatusOK, status)
+		movements := res["movements"].([]interface{})
+		assert.Len(t, movements, 1)
+		initial := movements[0].(map[string]interface{})
+		assert.Equal(t, "initial", initial["reason"])
+		assert.Equal(t, float64(5), initial["delta"])
+		assert.Equal(t, float64(5), initial["quantity_after"])
+	})
+
+	t.Run("retrieving lowers the quantity", func(t *testing.T) {
+		status, res := sendItemImageJSON(t, http.MethodPost, ledgerURL, token, map[string]interface{}{
+			"delta":  -2,
+			"reason": "retrieved",
+			"note":   "Taken to the cabin",
+		})
+		assert.Equal(t, http.StatusCreated, status)
+		assert.Equal(t, float64(3), res["quantity"])
+		movement := res["movement"].(map[string]interface{})
+		assert.Equal(t, float64(-2), movement["delta"])
+		assert.Equal(t, float64(3), movement["quantity_after"])
+		assert.Equal(t, "Taken to the cabin", movement["note"])
+	})
+
+	t.Run("quantity cannot drop below zero", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, ledgerURL, token, map[string]interface{}{
+			"delta":  -4,
+			"reason": "damaged",
+		})
+		assert.Equal(t, http.StatusConflict, status)
+
+		_, res := sendItemImageJSON(t, http.MethodGet, itemBaseURL+item["id"], token, nil)
+		assert.Equal(t, float64(3), res["quantity"])
+	})
+
+	t.Run("delta must match the reason", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, ledgerURL, token, map[string]interface{}{
+			"delta":  2,
+			"reason": "retrieved",
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("unknown reasons are rejected", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPost, ledgerURL, token, map[string]interface{}{
+			"delta":  1,
+			"reason": "found",
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("patching the quantity records a correction", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodPatch, itemBaseURL+item["id"], token, map[string]interface{}{"quantity": 7})
+		assert.Equal(t, http.StatusOK, status)
+
+		_, res := sendItemImageJSON(t, http.MethodGet, ledgerURL, token, nil)
+		movements := res["movements"].([]interface{})
+		assert.Len(t, movements, 3)
+		correction := movements[2].(map[string]interface{})
+		assert.Equal(t, "correction", correction["reason"])
+		assert.Equal(t, float64(4), correction["delta"])
+		assert.Equal(t, float64(7), correction["quantity_after"])
+	})
+
+	t.Run("other users cannot see the ledger", func(t *testing.T) {
+		status, _ := sendItemImageJSON(t, http.MethodGet, ledgerURL, otherToken, nil)
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
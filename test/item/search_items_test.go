@@ -0,0 +1,78 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func searchItems(t *testing.T, token, q string) (int, map[string]interface{}) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/items/search?q="+url.QueryEscape(q), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func TestSearchItems(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "searchpass123"
+	token := test.RegisterAndLogin(t, "search+"+timestamp+"@test.com", password)
+
+	var jacketBoxID string
+
+	t.Run("setup - add items to two boxes", func(t *testing.T) {
+		jacketBoxID = test.CreateSortPackedBox(t, token)
+		test.AddItemToBox(t, token, jacketBoxID, map[string]interface{}{
+			"name":        "Winter jacket",
+			"description": "Blue down jacket, size M",
+			"quantity":    1,
+		})
+
+		otherBoxID := test.CreateSortPackedBox(t, token)
+		test.AddItemToBox(t, token, otherBoxID, map[string]interface{}{
+			"name":        "Plates",
+			"description": "Set of winter-themed dinner plates",
+			"quantity":    6,
+		})
+	})
+
+	t.Run("finds item and its box, best match first", func(t *testing.T) {
+		status, res := searchItems(t, token, "winter jacket")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(1), res["total"])
+
+		results := res["results"].([]interface{})
+		hit := results[0].(map[string]interface{})
+		assert.Equal(t, "Winter jacket", hit["name"])
+		assert.Equal(t, jacketBoxID, hit["box_id"])
+		assert.Equal(t, "in_transit", hit["box_status"])
+	})
+
+	t.Run("stemmed single word matches both boxes", func(t *testing.T) {
+		status, res := searchItems(t, token, "winter")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(2), res["total"])
+	})
+
+	t.Run("other users do not see the items", func(t *testing.T) {
+		foreignToken := test.RegisterAndLogin(t, "searchB+"+timestamp+"@test.com", password)
+		status, res := searchItems(t, foreignToken, "jacket")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, float64(0), res["total"])
+	})
+
+	t.Run("query is required", func(t *testing.T) {
+		status, _ := searchItems(t, token, "")
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func uploadItemImage(t *testing.T, token, itemID, filename string, data []byte) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("image", filename)
+	_, _ = part.Write(data)
+	_ = writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/items/"+itemID+"/image", &body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func testPNG(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func TestUploadItemImage(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "imagepass123"
+	token := test.RegisterAndLogin(t, "image+"+timestamp+"@test.com", password)
+	otherToken := test.RegisterAndLogin(t, "image-other+"+timestamp+"@test.com", password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+	itemID := test.AddItemToBox(t, token, boxID, map[string]interface{}{
+		"name":     "Lamp",
+		"quantity": 1,
+	})["id"]
+
+	t.Run("upload returns signed image and thumbnail URLs", func(t *testing.T) {
+		status, res := uploadItemImage(t, token, itemID, "lamp.png", testPNG(800, 600))
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, res["image_url"], "signature=")
+		assert.Contains(t, res["thumbnail_url"], "signature=")
+
+		resp, err := http.Get(res["image_url"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+
+		resp, err = http.Get(res["thumbnail_url"].(string))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		thumb, _, err := image.DecodeConfig(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, 320, thumb.Width)
+		assert.Equal(t, 240, thumb.Height)
+	})
+
+	t.Run("item details return the signed URL", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/items/"+itemID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Contains(t, res["image_url"], "/blobs/items/"+itemID+"/")
+	})
+
+	t.Run("tampered signature is rejected", func(t *testing.T) {
+		_, res := uploadItemImage(t, token, itemID, "lamp.png", testPNG(10, 10))
+		url := res["image_url"].(string)
+		resp, err := http.Get(strings.Replace(url, "signature=", "signature=0", 1))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("non-image content is rejected", func(t *testing.T) {
+		status, _ := uploadItemImage(t, token, itemID, "notes.png", []byte("just some text"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, status)
+	})
+
+	t.Run("missing file field", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/items/"+itemID+"/image", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		data := append(testPNG(10, 10), make([]byte, 11<<20)...)
+		status, _ := uploadItemImage(t, token, itemID, "big.png", data)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	})
+
+	t.Run("other user cannot upload", func(t *testing.T) {
+		status, _ := uploadItemImage(t, otherToken, itemID, "lamp.png", testPNG(10, 10))
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+}
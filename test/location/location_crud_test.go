@@ -0,0 +1,115 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const locationBaseURL = "http://localhost:8080/locations"
+const boxBaseURL = "http://localhost:8080/boxes"
+
+func doJSON(t *testing.T, method, url, token string, payload interface{}) *http.Response {
+	var body []byte
+	if payload != nil {
+		body, _ = json.Marshal(payload)
+	}
+	req, _ := http.NewRequest(method, url, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func createLocation(t *testing.T, token string, capacity int) string {
+	resp := doJSON(t, http.MethodPost, locationBaseURL, token, map[string]interface{}{
+		"name":     "Shelf " + time.Now().Format("150405.000"),
+		"address":  "Warehouse 1",
+		"capacity": capacity,
+	})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var res map[string]string
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	assert.NotEmpty(t, res["id"])
+	return res["id"]
+}
+
+func TestLocationCRUD(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "locationpass123"
+	token := test.RegisterAndLogin(t, "location+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "locationadmin+"+timestamp+"@test.com", password)
+
+	var locationID string
+
+	t.Run("customer cannot create location", func(t *testing.T) {
+		resp := doJSON(t, http.MethodPost, locationBaseURL, token, map[string]interface{}{
+			"name":     "Shelf A",
+			"address":  "Warehouse 1",
+			"capacity": 10,
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("employee creates location", func(t *testing.T) {
+		locationID = createLocation(t, tokenEmployee, 10)
+	})
+
+	t.Run("invalid capacity", func(t *testing.T) {
+		resp := doJSON(t, http.MethodPost, locationBaseURL, tokenEmployee, map[string]interface{}{
+			"name":     "Shelf B",
+			"address":  "Warehouse 1",
+			"capacity": 0,
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("get location", func(t *testing.T) {
+		resp := doJSON(t, http.MethodGet, locationBaseURL+"/"+locationID, tokenEmployee, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res map[string]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Equal(t, float64(10), res["location"]["capacity"])
+		assert.Equal(t, float64(0), res["location"]["current_load"])
+	})
+
+	t.Run("update location", func(t *testing.T) {
+		resp := doJSON(t, http.MethodPatch, locationBaseURL+"/"+locationID, tokenEmployee, map[string]interface{}{
+			"capacity": 20,
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("list locations", func(t *testing.T) {
+		resp := doJSON(t, http.MethodGet, locationBaseURL, tokenEmployee, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res map[string][]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		found := false
+		for _, location := range res["locations"] {
+			if location["id"] == locationID {
+				found = true
+				assert.Equal(t, float64(20), location["capacity"])
+			}
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("delete location", func(t *testing.T) {
+		resp := doJSON(t, http.MethodDelete, locationBaseURL+"/"+locationID, tokenEmployee, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = doJSON(t, http.MethodGet, locationBaseURL+"/"+locationID, tokenEmployee, nil)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
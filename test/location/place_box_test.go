@@ -0,0 +1,102 @@
+package test
+
+import (
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getLoad(t *testing.T, token, locationID string) float64 {
+	resp := doJSON(t, http.MethodGet, locationBaseURL+"/"+locationID, token, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var res map[string]map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return res["location"]["current_load"].(float64)
+}
+
+func TestPlaceBox(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "placepass123"
+	token := test.RegisterAndLogin(t, "place+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "placeadmin+"+timestamp+"@test.com", password)
+
+	firstLocation := createLocation(t, tokenEmployee, 1)
+	secondLocation := createLocation(t, tokenEmployee, 5)
+	boxID := test.CreateSortPackedBox(t, token)
+
+	t.Run("customer cannot place box", func(t *testing.T) {
+		resp := doJSON(t, http.MethodPut, boxBaseURL+"/"+boxID+"/location", token, map[string]string{
+			"location_id": firstLocation,
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("place box at location", func(t *testing.T) {
+		resp := doJSON(t, http.MethodPut, boxBaseURL+"/"+boxID+"/location", tokenEmployee, map[string]string{
+			"location_id": firstLocation,
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, float64(1), getLoad(t, tokenEmployee, firstLocation))
+
+		resp = doJSON(t, http.MethodGet, boxBaseURL+"/"+boxID, token, nil)
+		var res map[string]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Equal(t, firstLocation, res["box"]["location_id"])
+	})
+
+	t.Run("placement rejected at capacity", func(t *testing.T) {
+		otherBoxID := test.CreateSortPackedBox(t, token)
+		resp := doJSON(t, http.MethodPut, boxBaseURL+"/"+otherBoxID+"/location", tokenEmployee, map[string]string{
+			"location_id": firstLocation,
+		})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("location holding boxes cannot be deleted", func(t *testing.T) {
+		resp := doJSON(t, http.MethodDelete, locationBaseURL+"/"+firstLocation, tokenEmployee, nil)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("move box between locations", func(t *testing.T) {
+		resp := doJSON(t, http.MethodPut, boxBaseURL+"/"+boxID+"/location", tokenEmployee, map[string]string{
+			"location_id": secondLocation,
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, float64(0), getLoad(t, tokenEmployee, firstLocation))
+		assert.Equal(t, float64(1), getLoad(t, tokenEmployee, secondLocation))
+	})
+
+	t.Run("remove box from location", func(t *testing.T) {
+		resp := doJSON(t, http.MethodDelete, boxBaseURL+"/"+boxID+"/location", tokenEmployee, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, float64(0), getLoad(t, tokenEmployee, secondLocation))
+	})
+
+	t.Run("unknown location", func(t *testing.T) {
+		resp := doJSON(t, http.MethodPut, boxBaseURL+"/"+boxID+"/location", tokenEmployee, map[string]string{
+			"location_id": "5b0c8a36-7c1e-4d2b-9a51-3f1e2d7c9b10",
+		})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("deleting a placed box frees its place", func(t *testing.T) {
+		placedBoxID := test.CreateSortPackedBox(t, token)
+		resp := doJSON(t, http.MethodPut, boxBaseURL+"/"+placedBoxID+"/location", tokenEmployee, map[string]string{
+			"location_id": firstLocation,
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, float64(1), getLoad(t, tokenEmployee, firstLocation))
+
+		resp = doJSON(t, http.MethodDelete, boxBaseURL+"/"+placedBoxID, token, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, float64(0), getLoad(t, tokenEmployee, firstLocation))
+
+		resp = doJSON(t, http.MethodDelete, locationBaseURL+"/"+firstLocation, tokenEmployee, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanBox(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "scanpass123"
+	token := test.RegisterAndLogin(t, "scan+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "scanadmin+"+timestamp+"@test.com", password)
+
+	locationID := createLocation(t, tokenEmployee, 5)
+
+	body, _ := json.Marshal(map[string]string{"packing_mode": "self", "item_name": "Skis", "item_note": "Pair of skis"})
+	req, _ := http.NewRequest(http.MethodPost, boxBaseURL, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	var created map[string]string
+	_ = json.NewDecoder(resp.Body).Decode(&created)
+	boxID := created["id"]
+
+	resp = doJSON(t, http.MethodGet, boxBaseURL+"/"+boxID, token, nil)
+	var got map[string]map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&got)
+	code, _ := got["box"]["code"].(string)
+
+	scan := func(t *testing.T, token, boxCode, location string) (*http.Response, map[string]interface{}) {
+		resp := doJSON(t, http.MethodPost, boxBaseURL+"/scan", token, map[string]string{
+			"box_code":    boxCode,
+			"location_id": location,
+		})
+		var res map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return resp, res
+	}
+
+	t.Run("customer cannot scan", func(t *testing.T) {
+		resp, _ := scan(t, token, code, locationID)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("check-in stores the box at the scanning location", func(t *testing.T) {
+		resp, res := scan(t, tokenEmployee, code, locationID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "in_transit", res["from_status"])
+		assert.Equal(t, "stored", res["to_status"])
+		assert.Equal(t, locationID, res["location_id"])
+		assert.Equal(t, float64(1), getLoad(t, tokenEmployee, locationID))
+	})
+
+	t.Run("stored box moves to pending pickup", func(t *testing.T) {
+		resp, res := scan(t, tokenEmployee, boxID, locationID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "pending_pickup", res["to_status"])
+		assert.Equal(t, locationID, res["location_id"])
+	})
+
+	t.Run("check-out returns the box and frees its slot", func(t *testing.T) {
+		resp, res := scan(t, tokenEmployee, code, locationID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "returned", res["to_status"])
+		assert.Nil(t, res["location_id"])
+		assert.Equal(t, float64(0), getLoad(t, tokenEmployee, locationID))
+	})
+
+	t.Run("returned box cannot be scanned", func(t *testing.T) {
+		resp, _ := scan(t, tokenEmployee, code, locationID)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("history records who scanned and where", func(t *testing.T) {
+		resp := doJSON(t, http.MethodGet, boxBaseURL+"/"+boxID+"/history", token, nil)
+		var res map[string][]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Len(t, res["history"], 3)
+		for _, event := range res["history"] {
+			assert.Equal(t, locationID, event["location_id"])
+			assert.Equal(t, "employee", event["account_type"])
+		}
+	})
+
+	t.Run("sort-packed box is checked in for packing", func(t *testing.T) {
+		sortBoxID := test.CreateSortPackedBox(t, token)
+		resp, res := scan(t, tokenEmployee, sortBoxID, locationID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "pending_pack", res["to_status"])
+	})
+
+	t.Run("unknown box", func(t *testing.T) {
+		resp, _ := scan(t, tokenEmployee, "ZZZZZZZZ", locationID)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("unknown location", func(t *testing.T) {
+		otherBoxID := test.CreateSortPackedBox(t, token)
+		resp, _ := scan(t, tokenEmployee, otherBoxID, uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("malformed code", func(t *testing.T) {
+		resp, _ := scan(t, tokenEmployee, "not a code", locationID)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
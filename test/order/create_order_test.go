@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const orderBaseURL = "http://localhost:8080/orders"
+
+func postOrder(t *testing.T, token string, order map[string]string) *http.Response {
+	body, _ := json.Marshal(order)
+	req, _ := http.NewRequest(http.MethodPost, orderBaseURL, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestCreateOrder(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	email := "order+" + timestamp + "@test.com"
+	password := "orderpass123"
+	token := test.RegisterAndLogin(t, email, password)
+
+	boxID := test.CreateSortPackedBox(t, token)
+	scheduled := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+
+	t.Run("create pickup order for in-transit box", func(t *testing.T) {
+		resp := postOrder(t, token, map[string]string{
+			"box_id":         boxID,
+			"type":           "pickup",
+			"scheduled_date": scheduled,
+		})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var res map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.NotEmpty(t, res["id"])
+	})
+
+	t.Run("second open order for same box conflicts", func(t *testing.T) {
+		resp := postOrder(t, token, map[string]string{
+			"box_id":         boxID,
+			"type":           "pickup",
+			"scheduled_date": scheduled,
+		})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("return order requires stored box", func(t *testing.T) {
+		otherBoxID := test.CreateSortPackedBox(t, token)
+		resp := postOrder(t, token, map[string]string{
+			"box_id":         otherBoxID,
+			"type":           "return",
+			"scheduled_date": scheduled,
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("relocate order forbidden for customers", func(t *testing.T) {
+		resp := postOrder(t, token, map[string]string{
+			"box_id":         boxID,
+			"type":           "relocate",
+			"scheduled_date": scheduled,
+		})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("scheduled date in the past", func(t *testing.T) {
+		otherBoxID := test.CreateSortPackedBox(t, token)
+		resp := postOrder(t, token, map[string]string{
+			"box_id":         otherBoxID,
+			"type":           "pickup",
+			"scheduled_date": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid order type", func(t *testing.T) {
+		resp := postOrder(t, token, map[string]string{
+			"box_id":         boxID,
+			"type":           "teleport",
+			"scheduled_date": scheduled,
+		})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("foreign user cannot order someone else's box", func(t *testing.T) {
+		foreignToken := test.RegisterAndLogin(t, "orderB+"+timestamp+"@test.com", password)
+		resp := postOrder(t, foreignToken, map[string]string{
+			"box_id":         test.CreateSortPackedBox(t, token),
+			"type":           "pickup",
+			"scheduled_date": scheduled,
+		})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("list returns created order", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, orderBaseURL, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res map[string][]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Equal(t, 1, len(res["orders"]))
+		assert.Equal(t, boxID, res["orders"][0]["box_id"])
+		assert.Equal(t, "requested", res["orders"][0]["status"])
+	})
+}
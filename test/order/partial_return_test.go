@@ -0,0 +1,157 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sendOrderJSON(t *testing.T, method, url, token string, payload interface{}) (int, map[string]interface{}) {
+	var body bytes.Buffer
+	if payload != nil {
+		_ = json.NewEncoder(&body).Encode(payload)
+	}
+	req, _ := http.NewRequest(method, url, &body)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+
+	var res map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&res)
+	return resp.StatusCode, res
+}
+
+func TestPartialReturn(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "returnpass123"
+	token := test.RegisterAndLogin(t, "partial+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "partialadmin+"+timestamp+"@test.com", password)
+	scheduled := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+
+	storeBox := func(t *testing.T, boxID string) {
+		status, _ := sendOrderJSON(t, http.MethodPatch, "http://localhost:8080/boxes/"+boxID+"/status", tokenEmployee, map[string]string{"status": "stored"})
+		assert.Equal(t, http.StatusOK, status)
+	}
+
+	boxID := test.CreateSortPackedBox(t, token)
+	books := test.AddItemToBox(t, token, boxID, map[string]interface{}{"name": "Books", "quantity": 10})
+	lamp := test.AddItemToBox(t, token, boxID, map[string]interface{}{"name": "Lamp", "quantity": 1})
+	storeBox(t, boxID)
+
+	var orderID string
+
+	t.Run("cannot request more than is in the box", func(t *testing.T) {
+		status, _ := sendOrderJSON(t, http.MethodPost, orderBaseURL, token, map[string]interface{}{
+			"box_id":         boxID,
+			"type":           "return",
+			"scheduled_date": scheduled,
+			"items":          []map[string]interface{}{{"item_id": books["id"], "quantity": 11}},
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("items are only allowed on returns", func(t *testing.T) {
+		status, _ := sendOrderJSON(t, http.MethodPost, orderBaseURL, tokenEmployee, map[string]interface{}{
+			"box_id":         boxID,
+			"type":           "relocate",
+			"scheduled_date": scheduled,
+			"items":          []map[string]interface{}{{"item_id": books["id"], "quantity": 1}},
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("request some items back", func(t *testing.T) {
+		status, res := sendOrderJSON(t, http.MethodPost, orderBaseURL, token, map[string]interface{}{
+			"box_id":         boxID,
+			"type":           "return",
+			"scheduled_date": scheduled,
+			"items": []map[string]interface{}{
+				{"item_id": books["id"], "quantity": 3},
+				{"item_id": lamp["id"], "quantity": 1},
+			},
+		})
+		assert.Equal(t, http.StatusCreated, status)
+		orderID = res["id"].(string)
+
+		_, res = sendOrderJSON(t, http.MethodGet, orderBaseURL+"/"+orderID, token, nil)
+		order := res["order"].(map[string]interface{})
+		assert.Len(t, order["items"], 2)
+	})
+
+	t.Run("employees get a pick list", func(t *testing.T) {
+		status, res := sendOrderJSON(t, http.MethodGet, orderBaseURL+"/"+orderID+"/pick-list", tokenEmployee, nil)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, false, res["whole_box"])
+		assert.NotEmpty(t, res["box_code"])
+		items := res["items"].([]interface{})
+		assert.Len(t, items, 2)
+		for _, raw := range items {
+			item := raw.(map[string]interface{})
+			if item["item_id"] == books["id"] {
+				assert.Equal(t, "Books", item["name"])
+				assert.Equal(t, float64(3), item["quantity"])
+				assert.Equal(t, float64(10), item["in_box"])
+			}
+		}
+	})
+
+	t.Run("customers cannot see the pick list", func(t *testing.T) {
+		status, _ := sendOrderJSON(t, http.MethodGet, orderBaseURL+"/"+orderID+"/pick-list", token, nil)
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+
+	t.Run("completing the order takes the items out and keeps the box stored", func(t *testing.T) {
+		resp := patchOrderStatus(t, tokenEmployee, orderID, "in_progress")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = patchOrderStatus(t, tokenEmployee, orderID, "completed")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, res := sendOrderJSON(t, http.MethodGet, "http://localhost:8080/items/"+books["id"], token, nil)
+		assert.Equal(t, float64(7), res["quantity"])
+		_, res = sendOrderJSON(t, http.MethodGet, "http://localhost:8080/items/"+lamp["id"], token, nil)
+		assert.Equal(t, float64(0), res["quantity"])
+
+		_, res = sendOrderJSON(t, http.MethodGet, "http://localhost:8080/boxes/"+boxID, token, nil)
+		assert.Equal(t, "stored", res["box"].(map[string]interface{})["status"])
+
+		_, res = sendOrderJSON(t, http.MethodGet, "http://localhost:8080/items/"+books["id"]+"/quantity-movements", token, nil)
+		movements := res["movements"].([]interface{})
+		last := movements[len(movements)-1].(map[string]interface{})
+		assert.Equal(t, "retrieved", last["reason"])
+		assert.Equal(t, float64(-3), last["delta"])
+	})
+
+	t.Run("self-packed boxes can only be returned whole", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"packing_mode": "self", "item_name": "Bike", "item_note": "Red"})
+		req, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/boxes", bytes.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		var created map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&created)
+		storeBox(t, created["id"])
+
+		status, _ := sendOrderJSON(t, http.MethodPost, orderBaseURL, token, map[string]interface{}{
+			"box_id":         created["id"],
+			"type":           "return",
+			"scheduled_date": scheduled,
+			"items":          []map[string]interface{}{{"item_id": books["id"], "quantity": 1}},
+		})
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		status, _ = sendOrderJSON(t, http.MethodPost, orderBaseURL, token, map[string]interface{}{
+			"box_id":         created["id"],
+			"type":           "return",
+			"scheduled_date": scheduled,
+		})
+		assert.Equal(t, http.StatusCreated, status)
+	})
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/sandroJayas/storage-service/test"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func patchOrderStatus(t *testing.T, token, orderID, status string) *http.Response {
+	body, _ := json.Marshal(map[string]string{"status": status})
+	req, _ := http.NewRequest(http.MethodPatch, orderBaseURL+"/"+orderID+"/status", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return resp
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	timestamp := time.Now().Format("150405")
+	password := "orderpass123"
+	token := test.RegisterAndLogin(t, "orderstatus+"+timestamp+"@test.com", password)
+	tokenEmployee := test.RegisterAndLoginEmployee(t, "orderadmin+"+timestamp+"@test.com", password)
+
+	createOrder := func(t *testing.T) string {
+		resp := postOrder(t, token, map[string]string{
+			"box_id":         test.CreateSortPackedBox(t, token),
+			"type":           "pickup",
+			"scheduled_date": time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339),
+		})
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var res map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		return res["id"]
+	}
+
+	t.Run("customer cannot start an order", func(t *testing.T) {
+		resp := patchOrderStatus(t, token, createOrder(t), "in_progress")
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("customer can cancel a requested order", func(t *testing.T) {
+		resp := patchOrderStatus(t, token, createOrder(t), "cancelled")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("employee moves order through lifecycle", func(t *testing.T) {
+		orderID := createOrder(t)
+
+		resp := patchOrderStatus(t, tokenEmployee, orderID, "completed")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = patchOrderStatus(t, tokenEmployee, orderID, "in_progress")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = patchOrderStatus(t, tokenEmployee, orderID, "completed")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		req, _ := http.NewRequest(http.MethodGet, orderBaseURL+"/"+orderID, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var res map[string]map[string]interface{}
+		_ = json.NewDecoder(resp.Body).Decode(&res)
+		assert.Equal(t, "completed", res["order"]["status"])
+
+		resp = patchOrderStatus(t, tokenEmployee, orderID, "cancelled")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		resp := patchOrderStatus(t, tokenEmployee, createOrder(t), "lost")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid order ID", func(t *testing.T) {
+		resp := patchOrderStatus(t, tokenEmployee, "not-a-uuid", "cancelled")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
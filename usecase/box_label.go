@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/utils"
+)
+
+// MaxLabelBatch caps how many labels one batch request may print.
+const MaxLabelBatch = 100
+
+var (
+	ErrUnsupportedLabelFormat = errors.New("format must be png or pdf")
+	ErrEmptyLabelBatch        = errors.New("box_ids must not be empty")
+	ErrLabelBatchTooLarge     = fmt.Errorf("at most %d labels can be printed at once", MaxLabelBatch)
+)
+
+// MissingBoxesError lists the requested boxes that do not exist.
+type MissingBoxesError struct {
+	IDs []uuid.UUID
+}
+
+func (e *MissingBoxesError) Error() string {
+	return fmt.Sprintf("%d boxes not found", len(e.IDs))
+}
+
+// labelSize is the width of the QR code on PDF labels in points (about 5 cm).
+const labelSize = 140.0
+
+// BoxLabel renders the label of one box as png or pdf. The QR code encodes the
+// box ID and the box code is printed underneath.
+func (s *BoxService) BoxLabel(ctx context.Context, boxID uuid.UUID, format string) ([]byte, error) {
+	if format != "png" && format != "pdf" {
+		return nil, ErrUnsupportedLabelFormat
+	}
+	box, err := s.repo.FindAnyByID(ctx, boxID)
+	if err != nil {
+		return nil, err
+	}
+	if format == "png" {
+		return utils.RenderLabelPNG(box.ID.String(), box.Code, box.ID.String())
+	}
+	return renderLabelsPDF([]models.Box{*box})
+}
+
+// BoxLabels renders a PDF with one label per box, in the order of ids.
+func (s *BoxService) BoxLabels(ctx context.Context, ids []uuid.UUID) ([]byte, error) {
+	if len(ids) == 0 {
+		return nil, ErrEmptyLabelBatch
+	}
+	if len(ids) > MaxLabelBatch {
+		return nil, ErrLabelBatchTooLarge
+	}
+
+	found, err := s.repo.FindAnyByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]models.Box, len(found))
+	for _, box := range found {
+		byID[box.ID] = box
+	}
+
+	boxes := make([]models.Box, 0, len(ids))
+	var missing []uuid.UUID
+	for _, id := range ids {
+		box, ok := byID[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		boxes = append(boxes, box)
+	}
+	if len(missing) > 0 {
+		return nil, &MissingBoxesError{IDs: missing}
+	}
+	return renderLabelsPDF(boxes)
+}
+
+func renderLabelsPDF(boxes []models.Box) ([]byte, error) {
+	var buf bytes.Buffer
+	pdf := utils.NewPDFWriter(&buf)
+	for i, box := range boxes {
+		bits, err := utils.QRBitmap(box.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			pdf.Space(30)
+		}
+		pdf.KeepTogether(labelSize + 40)
+		pdf.Bitmap(bits, labelSize, 0)
+		pdf.Line(box.Code, 20, true, 12)
+		pdf.Line(box.ID.String(), 8, false, 12)
+	}
+	if err := pdf.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/utils"
+)
+
+var (
+	ErrInvalidBoxCode    = errors.New("box_code must be a box code or box ID")
+	ErrScanWrongLocation = errors.New("box is not stored at the scanning location")
+)
+
+// ScanNotApplicableError is returned when scanning a box in its current status
+// has no meaning, e.g. a box that was already returned.
+type ScanNotApplicableError struct {
+	Status string
+}
+
+func (e *ScanNotApplicableError) Error() string {
+	return fmt.Sprintf("a %s box cannot be checked in or out by scanning", e.Status)
+}
+
+// ScanBox applies the next step of the box lifecycle when an employee scans a
+// box at a location. Boxes checked in are placed at that location and boxes
+// handed back to the customer are taken off their location. The status event
+// records the employee and the scanning location.
+func (s *BoxService) ScanBox(ctx context.Context, employee policy.Principal, req dto.ScanBoxRequest) (*dto.ScanBoxResponse, error) {
+	boxID, err := resolveBoxRef(ctx, s.repo, req.BoxCode)
+	if err != nil {
+		return nil, err
+	}
+	box, err := s.repo.FindAnyByID(ctx, boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	next, ok := scanStatus(box.Status, box.PackingMode)
+	if !ok {
+		return nil, &ScanNotApplicableError{Status: box.Status}
+	}
+	if err := checkStatusTransition(box.Status, next, employee); err != nil {
+		return nil, err
+	}
+	// A stored box is picked where it is stored
+	if next == "pending_pickup" && (box.LocationID == nil || *box.LocationID != req.LocationID) {
+		return nil, ErrScanWrongLocation
+	}
+
+	locationID := box.LocationID
+	switch next {
+	case "stored", "pending_pack":
+		locationID = &req.LocationID
+	case "returned":
+		locationID = nil
+	}
+
+	err = s.repo.UpdateStatusAndLocation(ctx, &models.BoxStatusEvent{
+		ID:          uuid.New(),
+		BoxID:       box.ID,
+		FromStatus:  box.Status,
+		ToStatus:    next,
+		ActorID:     employee.UserID,
+		AccountType: string(employee.Role),
+		Note:        req.Note,
+		LocationID:  &req.LocationID,
+	}, box.Version, locationID)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, ErrStatusChanged
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ScanBoxResponse{
+		BoxID:      box.ID,
+		Code:       box.Code,
+		FromStatus: box.Status,
+		ToStatus:   next,
+		LocationID: locationID,
+		Version:    box.Version + 1,
+	}, nil
+}
+
+// resolveBoxRef accepts a box ID or a box code as printed on the label.
+func resolveBoxRef(ctx context.Context, repo repository.BoxRepository, ref string) (uuid.UUID, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return id, nil
+	}
+	code, ok := utils.ParseBoxCode(ref)
+	if !ok {
+		return uuid.Nil, ErrInvalidBoxCode
+	}
+	return repo.FindIDByCode(ctx, code)
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanStoredBoxAtOtherLocation(t *testing.T) {
+	shelf, otherShelf := uuid.New(), uuid.New()
+	stored := &models.Box{ID: uuid.New(), UserID: uuid.New(), PackingMode: "self", Status: "stored", LocationID: &shelf}
+	unplaced := &models.Box{ID: uuid.New(), UserID: uuid.New(), PackingMode: "self", Status: "stored"}
+	service := NewBoxService(&fakeBoxRepository{boxes: map[uuid.UUID]*models.Box{stored.ID: stored, unplaced.ID: unplaced}}, nil, 0)
+	staff := policy.Principal{UserID: uuid.New(), Role: policy.WarehouseStaff}
+
+	for _, box := range []*models.Box{stored, unplaced} {
+		_, err := service.ScanBox(context.Background(), staff, dto.ScanBoxRequest{
+			BoxCode:    box.ID.String(),
+			LocationID: otherShelf,
+		})
+		assert.ErrorIs(t, err, ErrScanWrongLocation)
+	}
+}
@@ -2,21 +2,32 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"github.com/sandroJayas/storage-service/domain/blob"
 	"github.com/sandroJayas/storage-service/domain/repository"
 	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/sandroJayas/storage-service/utils"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sandroJayas/storage-service/dto"
 )
 
 type BoxService struct {
-	repo repository.BoxRepository
+	repo        repository.BoxRepository
+	blobs       blob.BlobStore
+	imageURLTTL time.Duration
 }
 
-func NewBoxService(repo repository.BoxRepository) *BoxService {
-	return &BoxService{repo}
+func NewBoxService(repo repository.BoxRepository, blobs blob.BlobStore, imageURLTTL time.Duration) *BoxService {
+	return &BoxService{repo: repo, blobs: blobs, imageURLTTL: imageURLTTL}
 }
 
+// maxBoxCodeAttempts bounds how often CreateBox draws a new code after a
+// collision. With 32^8 possible codes more than one attempt is already rare.
+const maxBoxCodeAttempts = 5
+
 func (s *BoxService) CreateBox(ctx context.Context, userID uuid.UUID, req dto.CreateBoxRequest) (uuid.UUID, error) {
 	box := &models.Box{
 		ID:          uuid.New(),
@@ -36,39 +47,125 @@ func (s *BoxService) CreateBox(ctx context.Context, userID uuid.UUID, req dto.Cr
 		}
 	}
 
-	err := s.repo.Create(ctx, box)
+	err := withNewBoxCode(box, func() error {
+		return s.repo.Create(ctx, box)
+	})
 	if err != nil {
 		return uuid.Nil, err
 	}
 	return box.ID, nil
 }
 
-func (s *BoxService) ListUserBoxes(ctx context.Context, userID uuid.UUID) ([]dto.BoxResponse, error) {
-	boxes, err := s.repo.FindByUserID(ctx, userID)
+// withNewBoxCode gives the box a fresh code and calls create, drawing a new
+// code whenever create reports repository.ErrBoxCodeTaken.
+func withNewBoxCode(box *models.Box, create func() error) error {
+	for attempt := 1; ; attempt++ {
+		code, err := utils.NewBoxCode()
+		if err != nil {
+			return err
+		}
+		box.Code = code
+
+		err = create()
+		if errors.Is(err, repository.ErrBoxCodeTaken) && attempt < maxBoxCodeAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+const defaultBoxPageSize = 50
+
+// ListUserBoxes returns one page of the user's boxes. Items are only loaded
+// when the query asks for them with include=items.
+func (s *BoxService) ListUserBoxes(ctx context.Context, userID uuid.UUID, query dto.ListBoxesQuery) (*dto.BoxListResponse, error) {
+	filter := repository.BoxFilter{
+		UserID:      &userID,
+		Status:      query.Status,
+		PackingMode: query.PackingMode,
+	}
+	if query.LocationID != "" {
+		locationID, err := uuid.Parse(query.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		filter.LocationID = &locationID
+	}
+	return s.listBoxes(ctx, filter, query)
+}
+
+var ErrInvalidDateRange = errors.New("created_from must be before created_to")
+
+// ListAllBoxes returns one page of boxes across all users, for employee work queues.
+func (s *BoxService) ListAllBoxes(ctx context.Context, query dto.AdminListBoxesQuery) (*dto.BoxListResponse, error) {
+	filter := repository.BoxFilter{
+		Status:      query.Status,
+		PackingMode: query.PackingMode,
+		CreatedFrom: query.CreatedFrom,
+		CreatedTo:   query.CreatedTo,
+	}
+	if !query.CreatedFrom.IsZero() && !query.CreatedTo.IsZero() && !query.CreatedFrom.Before(query.CreatedTo) {
+		return nil, ErrInvalidDateRange
+	}
+	if query.UserID != "" {
+		userID, err := uuid.Parse(query.UserID)
+		if err != nil {
+			return nil, err
+		}
+		filter.UserID = &userID
+	}
+	if query.LocationID != "" {
+		locationID, err := uuid.Parse(query.LocationID)
+		if err != nil {
+			return nil, err
+		}
+		filter.LocationID = &locationID
+	}
+	return s.listBoxes(ctx, filter, query.ListBoxesQuery)
+}
+
+func (s *BoxService) listBoxes(ctx context.Context, filter repository.BoxFilter, query dto.ListBoxesQuery) (*dto.BoxListResponse, error) {
+	page := repository.BoxPage{
+		Limit:        query.Limit,
+		Ascending:    query.Sort == "created_at",
+		IncludeItems: query.Include == "items",
+	}
+	if page.Limit == 0 {
+		page.Limit = defaultBoxPageSize
+	}
+	if query.Cursor != "" {
+		createdAt, id, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		page.AfterCreatedAt, page.AfterID = createdAt, id
+	}
+
+	// Fetch one extra row to know whether another page follows.
+	requested := page.Limit
+	page.Limit++
+	boxes, err := s.repo.FindPage(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.repo.Count(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 
-	var result []dto.BoxResponse
+	resp := &dto.BoxListResponse{
+		Boxes: make([]dto.BoxResponse, 0, len(boxes)),
+		Total: total,
+	}
+	if len(boxes) > requested {
+		boxes = boxes[:requested]
+		last := boxes[len(boxes)-1]
+		resp.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
 	for _, box := range boxes {
-		var items []dto.ItemDTO
-		for _, item := range box.Items {
-			items = append(items, dto.ItemDTO{
-				ID:          item.ID,
-				Name:        item.Name,
-				Description: item.Description,
-				Quantity:    item.Quantity,
-				ImageURL:    item.ImageURL,
-			})
-		}
-		result = append(result, dto.BoxResponse{
-			ID:          box.ID,
-			PackingMode: box.PackingMode,
-			Status:      box.Status,
-			Items:       items,
-		})
+		resp.Boxes = append(resp.Boxes, s.toBoxResponse(ctx, &box))
 	}
-	return result, nil
+	return resp, nil
 }
 
 func (s *BoxService) GetBoxByID(ctx context.Context, boxID uuid.UUID, userID uuid.UUID) (*dto.BoxResponse, error) {
@@ -77,34 +174,91 @@ func (s *BoxService) GetBoxByID(ctx context.Context, boxID uuid.UUID, userID uui
 		return nil, err
 	}
 
-	var items []dto.ItemDTO
-	for _, item := range box.Items {
-		items = append(items, dto.ItemDTO{
-			ID:          item.ID,
-			Name:        item.Name,
-			Description: item.Description,
-			Quantity:    item.Quantity,
-			ImageURL:    item.ImageURL,
-		})
+	resp := s.toBoxResponse(ctx, box)
+	return &resp, nil
+}
+
+// UpdateStatus applies a status change permitted by boxStatusTransitions for the
+// actor's role and records it in the box's status history. With ifMatch set the
+// box must still be at that version. Returned and disposed boxes are taken off
+// their location. It returns the new version.
+func (s *BoxService) UpdateStatus(ctx context.Context, boxID uuid.UUID, actor policy.Principal, newStatus string, note string, ifMatch *int) (int, error) {
+	box, err := s.repo.FindAnyByID(ctx, boxID)
+	if err != nil {
+		return 0, err
+	}
+	if ifMatch != nil && *ifMatch != box.Version {
+		return 0, repository.ErrVersionConflict
+	}
+	if err := checkStatusTransition(box.Status, newStatus, actor); err != nil {
+		return 0, err
 	}
 
-	resp := &dto.BoxResponse{
-		ID:          box.ID,
-		PackingMode: box.PackingMode,
-		Status:      box.Status,
-		Items:       items,
+	event := &models.BoxStatusEvent{
+		ID:          uuid.New(),
+		BoxID:       box.ID,
+		FromStatus:  box.Status,
+		ToStatus:    newStatus,
+		ActorID:     actor.UserID,
+		AccountType: string(actor.Role),
+		Note:        note,
 	}
-	return resp, nil
+	if newStatus == "returned" || newStatus == "disposed" {
+		// Boxes that left the warehouse give up their place, as when scanned out
+		err = s.repo.UpdateStatusAndLocation(ctx, event, box.Version, nil)
+	} else {
+		err = s.repo.UpdateStatus(ctx, event, box.Version)
+	}
+	if errors.Is(err, repository.ErrVersionConflict) && ifMatch == nil {
+		return 0, ErrStatusChanged
+	}
+	if err != nil {
+		return 0, err
+	}
+	return box.Version + 1, nil
 }
 
-func (s *BoxService) UpdateStatus(ctx context.Context, boxID uuid.UUID, newStatus string) error {
-	return s.repo.UpdateStatus(ctx, boxID, newStatus)
+func (s *BoxService) GetStatusHistory(ctx context.Context, boxID uuid.UUID) ([]dto.BoxStatusEventResponse, error) {
+	events, err := s.repo.FindStatusEvents(ctx, boxID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.BoxStatusEventResponse, 0, len(events))
+	for _, event := range events {
+		result = append(result, dto.BoxStatusEventResponse{
+			FromStatus:  event.FromStatus,
+			ToStatus:    event.ToStatus,
+			ActorID:     event.ActorID,
+			AccountType: event.AccountType,
+			Note:        event.Note,
+			LocationID:  event.LocationID,
+			CreatedAt:   event.CreatedAt,
+		})
+	}
+	return result, nil
 }
 
-func (s *BoxService) DeleteBox(ctx context.Context, boxID uuid.UUID) error {
-	return s.repo.SoftDelete(ctx, boxID)
+// DeleteBox soft deletes the box. With ifMatch set the box must still be at that version.
+func (s *BoxService) DeleteBox(ctx context.Context, boxID uuid.UUID, ifMatch *int) error {
+	return s.repo.SoftDelete(ctx, boxID, ifMatch)
 }
 
-func (s *BoxService) UpdateItem(ctx context.Context, boxID, itemID uuid.UUID, req dto.UpdateItemRequest) error {
-	return s.repo.UpdateItem(ctx, boxID, itemID, req)
+func (s *BoxService) toBoxResponse(ctx context.Context, box *models.Box) dto.BoxResponse {
+	var items []dto.ItemDTO
+	for _, item := range box.Items {
+		items = append(items, toItemDTO(ctx, s.blobs, s.imageURLTTL, &item))
+	}
+
+	return dto.BoxResponse{
+		ID:          box.ID,
+		Code:        box.Code,
+		UserID:      box.UserID,
+		PackingMode: box.PackingMode,
+		Status:      box.Status,
+		LocationID:  box.LocationID,
+		Items:       items,
+		CreatedAt:   box.CreatedAt,
+		Version:     box.Version,
+	}
 }
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusRecordingBoxRepository records which status update a box went through.
+type statusRecordingBoxRepository struct {
+	fakeBoxRepository
+	updated    []string
+	locationOf map[uuid.UUID]*uuid.UUID
+}
+
+func (r *statusRecordingBoxRepository) UpdateStatus(_ context.Context, event *models.BoxStatusEvent, _ int) error {
+	r.updated = append(r.updated, event.ToStatus)
+	return nil
+}
+
+func (r *statusRecordingBoxRepository) UpdateStatusAndLocation(_ context.Context, event *models.BoxStatusEvent, _ int, locationID *uuid.UUID) error {
+	r.updated = append(r.updated, event.ToStatus)
+	r.locationOf[event.BoxID] = locationID
+	return nil
+}
+
+func TestUpdateStatusFreesLocationOfBoxesLeavingTheWarehouse(t *testing.T) {
+	shelf := uuid.New()
+	pickedUp := &models.Box{ID: uuid.New(), UserID: uuid.New(), Status: "pending_pickup", LocationID: &shelf}
+	disposed := &models.Box{ID: uuid.New(), UserID: uuid.New(), Status: "stored", LocationID: &shelf}
+	stored := &models.Box{ID: uuid.New(), UserID: uuid.New(), Status: "stored", LocationID: &shelf}
+	repo := &statusRecordingBoxRepository{
+		fakeBoxRepository: fakeBoxRepository{boxes: map[uuid.UUID]*models.Box{pickedUp.ID: pickedUp, disposed.ID: disposed, stored.ID: stored}},
+		locationOf:        map[uuid.UUID]*uuid.UUID{},
+	}
+	service := NewBoxService(repo, nil, 0)
+	admin := policy.Principal{UserID: uuid.New(), Role: policy.Admin}
+
+	for box, status := range map[*models.Box]string{pickedUp: "returned", disposed: "disposed", stored: "pending_pickup"} {
+		_, err := service.UpdateStatus(context.Background(), box.ID, admin, status, "", nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	assert.ElementsMatch(t, []string{"returned", "disposed", "pending_pickup"}, repo.updated)
+	assert.Len(t, repo.locationOf, 2)
+	assert.Nil(t, repo.locationOf[pickedUp.ID])
+	assert.Nil(t, repo.locationOf[disposed.ID])
+	_, changed := repo.locationOf[stored.ID]
+	assert.False(t, changed, "other statuses keep the location")
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sandroJayas/storage-service/policy"
+)
+
+var ErrStatusChanged = errors.New("box status was changed by another request")
+
+// boxStatusTransitions is the box lifecycle. Any change not listed here is
+// rejected; who may make a listed change is decided by policy.SetBoxStatus.
+var boxStatusTransitions = map[string][]string{
+	"in_transit":     {"pending_pack", "stored"},
+	"pending_pack":   {"stored"},
+	"stored":         {"pending_pickup", "disposed"},
+	"pending_pickup": {"stored", "returned"},
+	"returned":       {"in_transit"},
+}
+
+// scanStatus returns the status a scan moves a box in the given status and
+// packing mode to, and false if scanning does not apply. Sort-packed boxes are
+// unpacked into items before they can be stored.
+func scanStatus(status, packingMode string) (string, bool) {
+	switch status {
+	case "in_transit":
+		if packingMode == "sort" {
+			return "pending_pack", true
+		}
+		return "stored", true
+	case "pending_pack":
+		return "stored", true
+	case "stored":
+		return "pending_pickup", true
+	case "pending_pickup":
+		return "returned", true
+	}
+	return "", false
+}
+
+// StatusTransitionError is returned when a box cannot move from its current
+// status to the requested one. Allowed lists the statuses the caller may move it to instead.
+type StatusTransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("cannot change box status from %s to %s", e.From, e.To)
+}
+
+// checkStatusTransition validates a status change against boxStatusTransitions
+// and returns a *policy.ForbiddenError if the actor may not make it.
+func checkStatusTransition(from, to string, actor policy.Principal) error {
+	if !contains(boxStatusTransitions[from], to) {
+		return &StatusTransitionError{From: from, To: to, Allowed: allowedNextStatuses(from, actor)}
+	}
+	return actor.Authorize(policy.SetBoxStatus(to))
+}
+
+func allowedNextStatuses(from string, actor policy.Principal) []string {
+	allowed := []string{}
+	for _, to := range boxStatusTransitions[from] {
+		if actor.Can(policy.SetBoxStatus(to)) {
+			allowed = append(allowed, to)
+		}
+	}
+	return allowed
+}
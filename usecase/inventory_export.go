@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/utils"
+)
+
+var ErrUnsupportedExportFormat = errors.New("format must be csv, json or pdf")
+
+// inventoryExporter writes inventory rows, which arrive grouped by box, in one
+// output format. Close finishes the document.
+type inventoryExporter interface {
+	WriteRow(row repository.InventoryRow) error
+	Close() error
+}
+
+// ExportInventory streams all of the user's boxes and items to w as csv, json
+// or pdf. Rows are written as they are read from the database.
+func (s *BoxService) ExportInventory(ctx context.Context, userID uuid.UUID, format string, w io.Writer) error {
+	var exporter inventoryExporter
+	switch format {
+	case "csv":
+		exporter = newCSVInventoryExporter(w)
+	case "json":
+		exporter = newJSONInventoryExporter(w)
+	case "pdf":
+		exporter = newPDFInventoryExporter(w)
+	default:
+		return ErrUnsupportedExportFormat
+	}
+
+	if err := s.repo.StreamInventory(ctx, userID, exporter.WriteRow); err != nil {
+		return err
+	}
+	return exporter.Close()
+}
+
+type csvInventoryExporter struct {
+	w *csv.Writer
+}
+
+func newCSVInventoryExporter(w io.Writer) *csvInventoryExporter {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"box_id", "box_code", "box_status", "packing_mode", "location", "box_created_at", "item_id", "item_name", "item_description", "item_quantity"})
+	return &csvInventoryExporter{w: cw}
+}
+
+func (e *csvInventoryExporter) WriteRow(row repository.InventoryRow) error {
+	record := []string{
+		row.BoxID.String(),
+		row.BoxCode,
+		row.BoxStatus,
+		row.PackingMode,
+		csvSafe(deref(row.LocationName)),
+		row.BoxCreatedAt.UTC().Format(time.RFC3339),
+		"", "", "", "",
+	}
+	if row.ItemID != nil {
+		record[6] = row.ItemID.String()
+		record[7] = csvSafe(deref(row.ItemName))
+		record[8] = csvSafe(deref(row.ItemDescription))
+		if row.ItemQuantity != nil {
+			record[9] = fmt.Sprint(*row.ItemQuantity)
+		}
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvInventoryExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvSafe stops spreadsheet apps from evaluating user text as a formula.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type inventoryBoxJSON struct {
+	ID          uuid.UUID           `json:"id"`
+	Code        string              `json:"code"`
+	Status      string              `json:"status"`
+	PackingMode string              `json:"packing_mode"`
+	Location    *string             `json:"location"`
+	CreatedAt   time.Time           `json:"created_at"`
+	Items       []inventoryItemJSON `json:"items"`
+}
+
+type inventoryItemJSON struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Quantity    int       `json:"quantity"`
+}
+
+// jsonInventoryExporter writes {"generated_at": ..., "boxes": [...]}, holding
+// only the items of the current box in memory.
+type jsonInventoryExporter struct {
+	w     io.Writer
+	box   *inventoryBoxJSON
+	count int
+	err   error
+}
+
+func newJSONInventoryExporter(w io.Writer) *jsonInventoryExporter {
+	e := &jsonInventoryExporter{w: w}
+	_, e.err = fmt.Fprintf(w, `{"generated_at":%q,"boxes":[`, time.Now().UTC().Format(time.RFC3339))
+	return e
+}
+
+func (e *jsonInventoryExporter) WriteRow(row repository.InventoryRow) error {
+	if e.box == nil || e.box.ID != row.BoxID {
+		e.flushBox()
+		e.box = &inventoryBoxJSON{
+			ID:          row.BoxID,
+			Code:        row.BoxCode,
+			Status:      row.BoxStatus,
+			PackingMode: row.PackingMode,
+			Location:    row.LocationName,
+			CreatedAt:   row.BoxCreatedAt,
+			Items:       []inventoryItemJSON{},
+		}
+	}
+	if row.ItemID != nil {
+		e.box.Items = append(e.box.Items, inventoryItemJSON{
+			ID:          *row.ItemID,
+			Name:        deref(row.ItemName),
+			Description: deref(row.ItemDescription),
+			Quantity:    derefInt(row.ItemQuantity),
+		})
+	}
+	return e.err
+}
+
+func (e *jsonInventoryExporter) flushBox() {
+	if e.box == nil || e.err != nil {
+		return
+	}
+	if e.count > 0 {
+		_, e.err = io.WriteString(e.w, ",")
+	}
+	if e.err == nil {
+		// Encoder appends a newline, which keeps large exports readable line by line
+		e.err = json.NewEncoder(e.w).Encode(e.box)
+	}
+	e.count++
+	e.box = nil
+}
+
+func (e *jsonInventoryExporter) Close() error {
+	e.flushBox()
+	if e.err != nil {
+		return e.err
+	}
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+// pdfInventoryExporter writes a printable manifest with a section per box.
+type pdfInventoryExporter struct {
+	pdf   *utils.PDFWriter
+	boxID uuid.UUID
+	boxes int
+}
+
+func newPDFInventoryExporter(w io.Writer) *pdfInventoryExporter {
+	pdf := utils.NewPDFWriter(w)
+	pdf.Line("Inventory manifest", 18, true, 0)
+	pdf.Line("Generated "+time.Now().UTC().Format("2 January 2006 15:04 MST"), 10, false, 0)
+	pdf.Space(10)
+	return &pdfInventoryExporter{pdf: pdf}
+}
+
+func (e *pdfInventoryExporter) WriteRow(row repository.InventoryRow) error {
+	if row.BoxID != e.boxID {
+		e.boxID = row.BoxID
+		e.boxes++
+		location := "not placed"
+		if row.LocationName != nil {
+			location = *row.LocationName
+		}
+		e.pdf.Space(8)
+		e.pdf.Line(fmt.Sprintf("Box %s", row.BoxCode), 12, true, 0)
+		e.pdf.Line("ID: "+row.BoxID.String(), 9, false, 0)
+		e.pdf.Line(fmt.Sprintf("Status: %s   Packing: %s   Location: %s   Since: %s",
+			row.BoxStatus, row.PackingMode, location, row.BoxCreatedAt.Format("2006-01-02")), 9, false, 0)
+		if row.ItemID == nil {
+			e.pdf.Line("No items recorded", 10, false, 12)
+		}
+	}
+	if row.ItemID != nil {
+		line := fmt.Sprintf("%d x %s", derefInt(row.ItemQuantity), deref(row.ItemName))
+		if description := deref(row.ItemDescription); description != "" {
+			line += " - " + description
+		}
+		e.pdf.Line(line, 10, false, 12)
+	}
+	return nil
+}
+
+func (e *pdfInventoryExporter) Close() error {
+	if e.boxes == 0 {
+		e.pdf.Line("No boxes stored.", 10, false, 0)
+	}
+	return e.pdf.Close()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefInt(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
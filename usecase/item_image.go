@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/blob"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxItemImageBytes is the largest image accepted by AddImage.
+	MaxItemImageBytes = 10 << 20
+	maxImagesPerItem  = 20
+	thumbnailMaxDim   = 320
+)
+
+var (
+	ErrImageTooLarge        = fmt.Errorf("image exceeds %d MB", MaxItemImageBytes>>20)
+	ErrUnsupportedImageType = errors.New("image must be JPEG, PNG or WebP")
+	ErrInvalidImage         = errors.New("image could not be decoded")
+	ErrImageDimensions      = utils.ErrImageTooManyPixels
+	ErrTooManyImages        = fmt.Errorf("an item can have at most %d images", maxImagesPerItem)
+)
+
+// imageExtensions lists the accepted content types, as sniffed from the upload
+// itself rather than taken from the client's Content-Type header.
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// AddImage stores a new image and its thumbnail after the item's existing
+// images. The item's first image, or one added with primary set, becomes primary.
+func (s *ItemService) AddImage(ctx context.Context, itemID, userID uuid.UUID, data []byte, caption string, primary bool) (dto.ItemImageDTO, error) {
+	if len(data) > MaxItemImageBytes {
+		return dto.ItemImageDTO{}, ErrImageTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return dto.ItemImageDTO{}, ErrUnsupportedImageType
+	}
+
+	item, err := s.itemRepo.GetByID(ctx, itemID, userID)
+	if err != nil {
+		return dto.ItemImageDTO{}, err
+	}
+	if len(item.Images) >= maxImagesPerItem {
+		return dto.ItemImageDTO{}, ErrTooManyImages
+	}
+
+	thumbnail, err := utils.GenerateThumbnail(data, thumbnailMaxDim)
+	if errors.Is(err, utils.ErrImageTooManyPixels) {
+		return dto.ItemImageDTO{}, ErrImageDimensions
+	}
+	if err != nil {
+		return dto.ItemImageDTO{}, ErrInvalidImage
+	}
+
+	prefix := fmt.Sprintf("items/%s/%s", item.ID, uuid.New())
+	image := &models.ItemImage{
+		ItemID:       item.ID,
+		Caption:      caption,
+		IsPrimary:    primary,
+		ImageKey:     prefix + "." + ext,
+		ThumbnailKey: prefix + "_thumb.jpg",
+	}
+	if err := s.blobs.Put(ctx, image.ImageKey, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return dto.ItemImageDTO{}, err
+	}
+	if err := s.blobs.Put(ctx, image.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+		s.deleteBlobs(ctx, image.ImageKey)
+		return dto.ItemImageDTO{}, err
+	}
+	if err := s.imageRepo.Create(ctx, image); err != nil {
+		s.deleteBlobs(ctx, image.ImageKey, image.ThumbnailKey)
+		return dto.ItemImageDTO{}, err
+	}
+
+	return toItemImageDTO(ctx, s.blobs, s.imageURLTTL, image), nil
+}
+
+func (s *ItemService) UpdateImage(ctx context.Context, itemID, imageID, userID uuid.UUID, req dto.UpdateItemImageRequest) (dto.ItemImageDTO, error) {
+	if _, err := s.itemRepo.GetByID(ctx, itemID, userID); err != nil {
+		return dto.ItemImageDTO{}, err
+	}
+	image, err := s.imageRepo.FindByID(ctx, itemID, imageID)
+	if err != nil {
+		return dto.ItemImageDTO{}, err
+	}
+
+	if req.Caption != nil {
+		image.Caption = *req.Caption
+	}
+	// Unsetting primary is ignored: an item with images always has one
+	if req.Primary != nil && *req.Primary {
+		image.IsPrimary = true
+	}
+	if err := s.imageRepo.Update(ctx, image); err != nil {
+		return dto.ItemImageDTO{}, err
+	}
+	return toItemImageDTO(ctx, s.blobs, s.imageURLTTL, image), nil
+}
+
+// ReorderImages sets the display order of all of the item's images.
+func (s *ItemService) ReorderImages(ctx context.Context, itemID, userID uuid.UUID, imageIDs []uuid.UUID) ([]dto.ItemImageDTO, error) {
+	if _, err := s.itemRepo.GetByID(ctx, itemID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.imageRepo.Reorder(ctx, itemID, imageIDs); err != nil {
+		return nil, err
+	}
+	images, err := s.imageRepo.ListByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return toItemImageDTOs(ctx, s.blobs, s.imageURLTTL, images), nil
+}
+
+// DeleteImage removes the image and its blobs. If it was the primary image the
+// next image in order becomes primary.
+func (s *ItemService) DeleteImage(ctx context.Context, itemID, imageID, userID uuid.UUID) error {
+	if _, err := s.itemRepo.GetByID(ctx, itemID, userID); err != nil {
+		return err
+	}
+	image, err := s.imageRepo.Delete(ctx, itemID, imageID)
+	if err != nil {
+		return err
+	}
+	s.deleteBlobs(ctx, image.ImageKey, image.ThumbnailKey)
+	return nil
+}
+
+// deleteBlobs removes blobs on a best-effort basis; orphans are only logged.
+func (s *ItemService) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			utils.Logger.Warn("failed to delete blob", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
+
+// toItemDTO maps an item to its response with signed URLs for its images.
+// image_url mirrors the primary image, or the legacy free-text URL without one.
+func toItemDTO(ctx context.Context, blobs blob.BlobStore, ttl time.Duration, item *models.Item) dto.ItemDTO {
+	result := dto.ItemDTO{
+		ID:          item.ID,
+		Name:        item.Name,
+		Description: item.Description,
+		Quantity:    item.Quantity,
+		ImageURL:    item.ImageURL,
+		Version:     item.Version,
+		Images:      toItemImageDTOs(ctx, blobs, ttl, item.Images),
+	}
+	for _, image := range result.Images {
+		if image.Primary {
+			result.ImageURL = image.URL
+			result.ThumbnailURL = image.ThumbnailURL
+		}
+	}
+	return result
+}
+
+func toItemImageDTOs(ctx context.Context, blobs blob.BlobStore, ttl time.Duration, images []models.ItemImage) []dto.ItemImageDTO {
+	result := make([]dto.ItemImageDTO, 0, len(images))
+	for i := range images {
+		result = append(result, toItemImageDTO(ctx, blobs, ttl, &images[i]))
+	}
+	return result
+}
+
+func toItemImageDTO(ctx context.Context, blobs blob.BlobStore, ttl time.Duration, image *models.ItemImage) dto.ItemImageDTO {
+	return dto.ItemImageDTO{
+		ID:           image.ID,
+		Position:     image.Position,
+		Caption:      image.Caption,
+		Primary:      image.IsPrimary,
+		URL:          signBlobURL(ctx, blobs, ttl, image.ImageKey),
+		ThumbnailURL: signBlobURL(ctx, blobs, ttl, image.ThumbnailKey),
+		CreatedAt:    image.CreatedAt,
+	}
+}
+
+func signBlobURL(ctx context.Context, blobs blob.BlobStore, ttl time.Duration, key string) string {
+	url, err := blobs.SignedURL(ctx, key, ttl)
+	if err != nil {
+		utils.Logger.Warn("failed to sign blob URL", zap.String("key", key), zap.Error(err))
+		return ""
+	}
+	return url
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+)
+
+// MaxItemImportRows caps a single bulk import.
+const MaxItemImportRows = 1000
+
+var (
+	ErrEmptyImport    = errors.New("import contains no items")
+	ErrImportTooLarge = fmt.Errorf("an import can contain at most %d items", MaxItemImportRows)
+	ErrInvalidCSV     = errors.New("invalid CSV")
+)
+
+// ItemImportError is returned when rows of a bulk import fail validation.
+// Nothing is imported in that case.
+type ItemImportError struct {
+	Rows []dto.ItemImportRowError
+}
+
+func (e *ItemImportError) Error() string {
+	return fmt.Sprintf("%d rows failed validation", len(e.Rows))
+}
+
+// itemValidator checks rows against the same binding tags gin uses for
+// POST /boxes/:id/items, reporting fields by their JSON names.
+var itemValidator = func() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("binding")
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("json"), ",")[0]
+	})
+	return v
+}()
+
+// ImportItems validates every row and then adds all items to the box in one
+// transaction. Like AddItem it only accepts sort-packed boxes of the caller,
+// or any box with policy.BoxWriteAny.
+func (s *ItemService) ImportItems(ctx context.Context, boxID uuid.UUID, caller policy.Principal, rows []dto.AddItemRequest) ([]uuid.UUID, error) {
+	return s.importItems(ctx, boxID, caller, rows, nil)
+}
+
+// ImportItemsCSV is ImportItems for a CSV upload. The first line is a header
+// naming the columns (name, description, quantity, image_url) in any order;
+// name and quantity are required.
+func (s *ItemService) ImportItemsCSV(ctx context.Context, boxID uuid.UUID, caller policy.Principal, r io.Reader) ([]uuid.UUID, error) {
+	rows, parseErrors, err := parseItemCSV(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.importItems(ctx, boxID, caller, rows, parseErrors)
+}
+
+// importItems adds rows to the box. parseErrors holds field errors found while
+// decoding, by row index and field; they replace validation errors for the same field.
+func (s *ItemService) importItems(ctx context.Context, boxID uuid.UUID, caller policy.Principal, rows []dto.AddItemRequest, parseErrors map[int]map[string]string) ([]uuid.UUID, error) {
+	if len(rows) == 0 {
+		return nil, ErrEmptyImport
+	}
+	if len(rows) > MaxItemImportRows {
+		return nil, ErrImportTooLarge
+	}
+
+	box, err := s.findAccessibleBox(ctx, boxID, caller, policy.BoxWriteAny)
+	if err != nil {
+		return nil, err
+	}
+	if box.PackingMode != "sort" {
+		return nil, ErrBoxNotSortPacked
+	}
+
+	var rowErrors []dto.ItemImportRowError
+	for i, row := range rows {
+		fieldErrs := validateImportRow(row)
+		for field, msg := range parseErrors[i] {
+			fieldErrs[field] = msg
+		}
+		if len(fieldErrs) == 0 {
+			continue
+		}
+		msgs := make([]string, 0, len(fieldErrs))
+		for _, field := range []string{"name", "description", "quantity", "image_url"} {
+			if msg, ok := fieldErrs[field]; ok {
+				msgs = append(msgs, msg)
+			}
+		}
+		rowErrors = append(rowErrors, dto.ItemImportRowError{Row: i + 1, Errors: msgs})
+	}
+	if len(rowErrors) > 0 {
+		return nil, &ItemImportError{Rows: rowErrors}
+	}
+
+	items := make([]models.Item, len(rows))
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = uuid.New()
+		items[i] = models.Item{
+			ID:          ids[i],
+			BoxID:       box.ID,
+			Name:        row.Name,
+			Description: row.Description,
+			Quantity:    row.Quantity,
+			ImageURL:    row.ImageURL,
+		}
+	}
+	if err := s.itemRepo.CreateBatch(ctx, items); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// validateImportRow returns an error message per invalid field, keyed by JSON name.
+func validateImportRow(row dto.AddItemRequest) map[string]string {
+	msgs := map[string]string{}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(itemValidator.Struct(row), &fieldErrs) {
+		return msgs
+	}
+	for _, fe := range fieldErrs {
+		switch fe.Tag() {
+		case "required":
+			msgs[fe.Field()] = fe.Field() + " is required"
+		case "min":
+			msgs[fe.Field()] = fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+		case "max":
+			msgs[fe.Field()] = fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+		default:
+			msgs[fe.Field()] = fe.Field() + " is invalid"
+		}
+	}
+	return msgs
+}
+
+// csvImportColumns are the columns accepted in a CSV import header.
+var csvImportColumns = map[string]bool{"name": true, "description": true, "quantity": true, "image_url": true}
+
+// parseItemCSV reads a CSV import into rows. Values that cannot be decoded are
+// reported per row index and field instead of failing the whole import.
+func parseItemCSV(r io.Reader) ([]dto.AddItemRequest, map[int]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, ErrEmptyImport
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !csvImportColumns[name] {
+			return nil, nil, fmt.Errorf("%w: unknown column %q", ErrInvalidCSV, name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "quantity"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("%w: missing column %q", ErrInvalidCSV, required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []dto.AddItemRequest
+	parseErrors := map[int]map[string]string{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		if len(rows) == MaxItemImportRows {
+			return nil, nil, ErrImportTooLarge
+		}
+
+		row := dto.AddItemRequest{
+			Name:        field(record, "name"),
+			Description: field(record, "description"),
+			ImageURL:    field(record, "image_url"),
+		}
+		if raw := field(record, "quantity"); raw != "" {
+			quantity, err := strconv.Atoi(raw)
+			if err != nil {
+				parseErrors[len(rows)] = map[string]string{"quantity": "quantity must be a whole number"}
+			}
+			row.Quantity = quantity
+		}
+		rows = append(rows, row)
+	}
+	return rows, parseErrors, nil
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseItemCSV(t *testing.T) {
+	input := "quantity,name,description\n" +
+		"2,Plates,Blue\n" +
+		"lots,Cups,\n" +
+		"1,\"Lamp, brass\",\"Dented, works\"\n"
+
+	rows, parseErrors, err := parseItemCSV(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.AddItemRequest{
+		{Name: "Plates", Description: "Blue", Quantity: 2},
+		{Name: "Cups"},
+		{Name: "Lamp, brass", Description: "Dented, works", Quantity: 1},
+	}, rows)
+	assert.Equal(t, map[int]map[string]string{1: {"quantity": "quantity must be a whole number"}}, parseErrors)
+}
+
+func TestParseItemCSVRejectsBadHeaders(t *testing.T) {
+	for name, input := range map[string]string{
+		"unknown column":   "name,quantity,colour\nLamp,1,red\n",
+		"missing quantity": "name,description\nLamp,brass\n",
+		"ragged row":       "name,quantity\nLamp,1,extra\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := parseItemCSV(strings.NewReader(input))
+			assert.ErrorIs(t, err, ErrInvalidCSV)
+		})
+	}
+
+	_, _, err := parseItemCSV(strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrEmptyImport)
+}
+
+func TestValidateImportRow(t *testing.T) {
+	assert.Empty(t, validateImportRow(dto.AddItemRequest{Name: "Lamp", Quantity: 1}))
+	assert.Equal(t, map[string]string{
+		"name":     "name is required",
+		"quantity": "quantity is required",
+	}, validateImportRow(dto.AddItemRequest{}))
+	assert.Equal(t, map[string]string{
+		"quantity": "quantity must be at least 1",
+	}, validateImportRow(dto.AddItemRequest{Name: "Lamp", Quantity: -2}))
+}
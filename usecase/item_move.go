@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+var (
+	ErrSameBox   = errors.New("source and target box must be different")
+	ErrBoxClosed = repository.ErrBoxClosed
+)
+
+// MoveItems moves items from one of the user's sort-packed boxes to another.
+func (s *ItemService) MoveItems(ctx context.Context, userID, fromBoxID uuid.UUID, req dto.MoveItemsRequest) error {
+	from, to, err := s.consolidationBoxes(ctx, userID, fromBoxID, req.TargetBox)
+	if err != nil {
+		return err
+	}
+	return s.moveRepo.MoveItems(ctx, from.ID, to.ID, uniqueIDs(req.ItemIDs), userID)
+}
+
+// MergeBoxes empties the source box into the target box and deletes it. Both
+// must be sort-packed boxes of the user.
+func (s *ItemService) MergeBoxes(ctx context.Context, userID, targetBoxID uuid.UUID, req dto.MergeBoxesRequest) error {
+	target, source, err := s.consolidationBoxes(ctx, userID, targetBoxID, req.SourceBox)
+	if err != nil {
+		return err
+	}
+	return s.moveRepo.MergeBoxes(ctx, target.ID, source.ID, userID)
+}
+
+// SplitBox moves items into a new sort-packed box with the same status and
+// location as the original one.
+func (s *ItemService) SplitBox(ctx context.Context, userID, fromBoxID uuid.UUID, req dto.SplitBoxRequest) (*dto.SplitBoxResponse, error) {
+	from, err := s.boxRepo.FindByID(ctx, fromBoxID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConsolidationBox(from); err != nil {
+		return nil, err
+	}
+
+	box := &models.Box{
+		ID:          uuid.New(),
+		UserID:      userID,
+		PackingMode: from.PackingMode,
+		Status:      from.Status,
+		LocationID:  from.LocationID,
+	}
+	err = withNewBoxCode(box, func() error {
+		return s.moveRepo.SplitBox(ctx, box, from.ID, uniqueIDs(req.ItemIDs), userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SplitBoxResponse{ID: box.ID, Code: box.Code}, nil
+}
+
+// GetItemHistory lists the moves of one of the user's items, oldest first.
+func (s *ItemService) GetItemHistory(ctx context.Context, itemID, userID uuid.UUID) ([]dto.ItemMoveResponse, error) {
+	if _, err := s.itemRepo.GetByID(ctx, itemID, userID); err != nil {
+		return nil, err
+	}
+	moves, err := s.moveRepo.ListByItemID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.ItemMoveResponse, 0, len(moves))
+	for _, move := range moves {
+		result = append(result, dto.ItemMoveResponse{
+			FromBoxID: move.FromBoxID,
+			ToBoxID:   move.ToBoxID,
+			Reason:    move.Reason,
+			ActorID:   move.ActorID,
+			CreatedAt: move.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// consolidationBoxes loads the box from the path and the other box named by ID
+// or code, both of which must belong to the user and accept moved items.
+func (s *ItemService) consolidationBoxes(ctx context.Context, userID, boxID uuid.UUID, otherRef string) (*models.Box, *models.Box, error) {
+	box, err := s.boxRepo.FindByID(ctx, boxID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	otherID, err := resolveBoxRef(ctx, s.boxRepo, otherRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	if otherID == box.ID {
+		return nil, nil, ErrSameBox
+	}
+	other, err := s.boxRepo.FindByID(ctx, otherID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, b := range []*models.Box{box, other} {
+		if err := checkConsolidationBox(b); err != nil {
+			return nil, nil, err
+		}
+	}
+	return box, other, nil
+}
+
+// checkConsolidationBox applies the packing rule of AddItem: only sort-packed
+// boxes have their items managed by us.
+func checkConsolidationBox(box *models.Box) error {
+	if box.PackingMode != "sort" {
+		return ErrBoxNotSortPacked
+	}
+	if box.Status == "returned" || box.Status == "disposed" {
+		return ErrBoxClosed
+	}
+	return nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+)
+
+var ErrDeltaSign = errors.New("added needs a positive delta, retrieved and damaged a negative one")
+
+// AdjustQuantity changes an item's quantity by req.Delta and records why in the
+// item's quantity ledger, with the caller as actor. The caller must own the
+// item's box or hold box:write_any. With ifMatch set the item must still be at
+// that version.
+func (s *ItemService) AdjustQuantity(ctx context.Context, itemID uuid.UUID, caller policy.Principal, req dto.QuantityAdjustmentRequest, ifMatch *int) (*dto.QuantityAdjustmentResponse, error) {
+	switch {
+	case req.Reason == "added" && req.Delta < 0,
+		(req.Reason == "retrieved" || req.Reason == "damaged") && req.Delta > 0:
+		return nil, ErrDeltaSign
+	}
+	if _, err := s.findAccessibleItem(ctx, itemID, caller, policy.BoxWriteAny); err != nil {
+		return nil, err
+	}
+
+	movement := &models.ItemQuantityMovement{
+		ID:      uuid.New(),
+		ItemID:  itemID,
+		Delta:   req.Delta,
+		Reason:  req.Reason,
+		Note:    req.Note,
+		ActorID: caller.UserID,
+	}
+	item, err := s.itemRepo.AdjustQuantity(ctx, movement, ifMatch)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.QuantityAdjustmentResponse{
+		Quantity: item.Quantity,
+		Version:  item.Version,
+		Movement: toQuantityMovementResponse(movement),
+	}, nil
+}
+
+// ListQuantityMovements returns an item's quantity ledger, oldest first. The
+// caller must own the item's box or hold box:read_any.
+func (s *ItemService) ListQuantityMovements(ctx context.Context, itemID uuid.UUID, caller policy.Principal) ([]dto.QuantityMovementResponse, error) {
+	if _, err := s.findAccessibleItem(ctx, itemID, caller, policy.BoxReadAny); err != nil {
+		return nil, err
+	}
+	movements, err := s.itemRepo.ListQuantityMovements(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.QuantityMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		result = append(result, toQuantityMovementResponse(&movement))
+	}
+	return result, nil
+}
+
+func toQuantityMovementResponse(movement *models.ItemQuantityMovement) dto.QuantityMovementResponse {
+	return dto.QuantityMovementResponse{
+		Delta:         movement.Delta,
+		QuantityAfter: movement.QuantityAfter,
+		Reason:        movement.Reason,
+		Note:          movement.Note,
+		ActorID:       movement.ActorID,
+		CreatedAt:     movement.CreatedAt,
+	}
+}
@@ -2,33 +2,47 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"github.com/sandroJayas/storage-service/domain/blob"
 	"github.com/sandroJayas/storage-service/domain/repository"
 	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sandroJayas/storage-service/dto"
 )
 
+var ErrBoxNotSortPacked = repository.ErrBoxNotSortPacked
+
 type ItemService struct {
-	itemRepo repository.ItemRepository
-	boxRepo  repository.BoxRepository
+	itemRepo    repository.ItemRepository
+	boxRepo     repository.BoxRepository
+	imageRepo   repository.ItemImageRepository
+	moveRepo    repository.ItemMoveRepository
+	blobs       blob.BlobStore
+	imageURLTTL time.Duration
 }
 
-func NewItemService(itemRepo repository.ItemRepository, boxRepo repository.BoxRepository) *ItemService {
+func NewItemService(itemRepo repository.ItemRepository, boxRepo repository.BoxRepository, imageRepo repository.ItemImageRepository, moveRepo repository.ItemMoveRepository, blobs blob.BlobStore, imageURLTTL time.Duration) *ItemService {
 	return &ItemService{
-		itemRepo: itemRepo,
-		boxRepo:  boxRepo,
+		itemRepo:    itemRepo,
+		boxRepo:     boxRepo,
+		imageRepo:   imageRepo,
+		moveRepo:    moveRepo,
+		blobs:       blobs,
+		imageURLTTL: imageURLTTL,
 	}
 }
 
-func (s *ItemService) AddItem(ctx context.Context, boxID, userID uuid.UUID, req dto.AddItemRequest) (uuid.UUID, error) {
-	box, err := s.boxRepo.FindByID(ctx, boxID, userID)
+// AddItem adds an item to one of the caller's boxes, or to any box with
+// policy.BoxWriteAny.
+func (s *ItemService) AddItem(ctx context.Context, boxID uuid.UUID, caller policy.Principal, req dto.AddItemRequest) (uuid.UUID, error) {
+	box, err := s.findAccessibleBox(ctx, boxID, caller, policy.BoxWriteAny)
 	if err != nil {
 		return uuid.Nil, err
 	}
 	if box.PackingMode != "sort" {
-		return uuid.Nil, errors.New("can only add items to sort-packed boxes")
+		return uuid.Nil, ErrBoxNotSortPacked
 	}
 	item := &models.Item{
 		BoxID:       box.ID,
@@ -46,8 +60,10 @@ func (s *ItemService) AddItem(ctx context.Context, boxID, userID uuid.UUID, req
 	return item.ID, nil
 }
 
-func (s *ItemService) ListBoxItems(ctx context.Context, boxID, userID uuid.UUID) ([]dto.ItemDTO, error) {
-	box, err := s.boxRepo.FindByID(ctx, boxID, userID)
+// ListBoxItems lists the items of one of the caller's boxes, or of any box with
+// policy.BoxReadAny.
+func (s *ItemService) ListBoxItems(ctx context.Context, boxID uuid.UUID, caller policy.Principal) ([]dto.ItemDTO, error) {
+	box, err := s.findAccessibleBox(ctx, boxID, caller, policy.BoxReadAny)
 	if err != nil {
 		return nil, err
 	}
@@ -58,37 +74,48 @@ func (s *ItemService) ListBoxItems(ctx context.Context, boxID, userID uuid.UUID)
 
 	var result []dto.ItemDTO
 	for _, item := range items {
-		result = append(result, dto.ItemDTO{
-			ID:          item.ID,
-			Name:        item.Name,
-			Description: item.Description,
-			Quantity:    item.Quantity,
-			ImageURL:    item.ImageURL,
-		})
+		result = append(result, toItemDTO(ctx, s.blobs, s.imageURLTTL, &item))
 	}
 
 	return result, nil
 }
 
+// findAccessibleBox returns the box if the caller owns it or holds perm.
+func (s *ItemService) findAccessibleBox(ctx context.Context, boxID uuid.UUID, caller policy.Principal, perm policy.Permission) (*models.Box, error) {
+	if caller.Can(perm) {
+		return s.boxRepo.FindAnyByID(ctx, boxID)
+	}
+	return s.boxRepo.FindByID(ctx, boxID, caller.UserID)
+}
+
+// findAccessibleItem returns the item if the caller owns its box or holds perm.
+func (s *ItemService) findAccessibleItem(ctx context.Context, itemID uuid.UUID, caller policy.Principal, perm policy.Permission) (*models.Item, error) {
+	if caller.Can(perm) {
+		return s.itemRepo.GetAnyByID(ctx, itemID)
+	}
+	return s.itemRepo.GetByID(ctx, itemID, caller.UserID)
+}
+
 func (s *ItemService) GetItem(ctx context.Context, itemID, userID uuid.UUID) (dto.ItemDTO, error) {
 	item, err := s.itemRepo.GetByID(ctx, itemID, userID)
 	if err != nil {
 		return dto.ItemDTO{}, err
 	}
 
-	return dto.ItemDTO{
-		ID:          item.ID,
-		Name:        item.Name,
-		Description: item.Description,
-		Quantity:    item.Quantity,
-		ImageURL:    item.ImageURL,
-	}, nil
+	return toItemDTO(ctx, s.blobs, s.imageURLTTL, item), nil
 }
 
-func (s *ItemService) UpdateItem(ctx context.Context, itemID, userID uuid.UUID, req dto.UpdateItemRequest) error {
+// UpdateItem applies the given fields and returns the item's new version. A new
+// quantity is recorded in the quantity ledger as a correction. With
+// ifMatch set the item must still be at that version. Either way the write fails
+// with repository.ErrVersionConflict if the item changed since it was read here.
+func (s *ItemService) UpdateItem(ctx context.Context, itemID, userID uuid.UUID, req dto.UpdateItemRequest, ifMatch *int) (int, error) {
 	item, err := s.itemRepo.GetByID(ctx, itemID, userID)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if ifMatch != nil && *ifMatch != item.Version {
+		return 0, repository.ErrVersionConflict
 	}
 
 	if req.Name != nil {
@@ -98,15 +125,53 @@ func (s *ItemService) UpdateItem(ctx context.Context, itemID, userID uuid.UUID,
 		item.Description = *req.Description
 	}
 	if req.Quantity != nil {
+		if *req.Quantity < 0 {
+			return 0, repository.ErrNegativeQuantity
+		}
 		item.Quantity = *req.Quantity
 	}
 	if req.ImageURL != nil {
 		item.ImageURL = *req.ImageURL
 	}
 
-	return s.itemRepo.Update(ctx, item)
+	if err := s.itemRepo.Update(ctx, item, userID); err != nil {
+		return 0, err
+	}
+	return item.Version, nil
+}
+
+const defaultItemSearchPageSize = 20
+
+// SearchItems finds the user's items by name and description, best matches first.
+func (s *ItemService) SearchItems(ctx context.Context, userID uuid.UUID, query dto.ItemSearchQuery) (*dto.ItemSearchResponse, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultItemSearchPageSize
+	}
+
+	hits, total, err := s.itemRepo.Search(ctx, userID, query.Q, limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ItemSearchResponse{
+		Results: make([]dto.ItemSearchHit, 0, len(hits)),
+		Total:   total,
+	}
+	for _, hit := range hits {
+		resp.Results = append(resp.Results, dto.ItemSearchHit{
+			ItemDTO:      toItemDTO(ctx, s.blobs, s.imageURLTTL, &hit.Item),
+			BoxID:        hit.BoxID,
+			BoxStatus:    hit.BoxStatus,
+			LocationID:   hit.LocationID,
+			LocationName: hit.LocationName,
+			Rank:         hit.Rank,
+		})
+	}
+	return resp, nil
 }
 
-func (s *ItemService) DeleteItem(ctx context.Context, itemID, userID uuid.UUID) error {
-	return s.itemRepo.Delete(ctx, itemID)
+// DeleteItem deletes the item. With ifMatch set the item must still be at that version.
+func (s *ItemService) DeleteItem(ctx context.Context, itemID, userID uuid.UUID, ifMatch *int) error {
+	return s.itemRepo.Delete(ctx, itemID, ifMatch)
 }
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// fakeBoxRepository implements the box lookups; other methods are not used.
+type fakeBoxRepository struct {
+	repository.BoxRepository
+	boxes map[uuid.UUID]*models.Box
+}
+
+func (r *fakeBoxRepository) FindByID(_ context.Context, id, userID uuid.UUID) (*models.Box, error) {
+	box, ok := r.boxes[id]
+	if !ok || box.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return box, nil
+}
+
+func (r *fakeBoxRepository) FindAnyByID(_ context.Context, id uuid.UUID) (*models.Box, error) {
+	box, ok := r.boxes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return box, nil
+}
+
+// fakeItemRepository implements adding and listing items; other methods are not used.
+type fakeItemRepository struct {
+	repository.ItemRepository
+	items []models.Item
+}
+
+func (r *fakeItemRepository) Create(_ context.Context, item *models.Item) error {
+	item.ID = uuid.New()
+	r.items = append(r.items, *item)
+	return nil
+}
+
+func (r *fakeItemRepository) CreateBatch(_ context.Context, items []models.Item) error {
+	r.items = append(r.items, items...)
+	return nil
+}
+
+func (r *fakeItemRepository) ListByBoxID(_ context.Context, boxID uuid.UUID) ([]models.Item, error) {
+	var items []models.Item
+	for _, item := range r.items {
+		if item.BoxID == boxID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func TestItemServiceBoxAccess(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	box := &models.Box{ID: uuid.New(), UserID: customerID, PackingMode: "sort", Status: "stored"}
+	itemRepo := &fakeItemRepository{}
+	s := NewItemService(itemRepo, &fakeBoxRepository{boxes: map[uuid.UUID]*models.Box{box.ID: box}}, nil, nil, nil, 0)
+
+	staff := policy.Principal{UserID: uuid.New(), Role: policy.WarehouseStaff}
+	support := policy.Principal{UserID: uuid.New(), Role: policy.Support}
+	stranger := policy.Principal{UserID: uuid.New(), Role: policy.Customer}
+	owner := policy.Principal{UserID: customerID, Role: policy.Customer}
+
+	t.Run("staff add and list items in a customer's box", func(t *testing.T) {
+		_, err := s.AddItem(ctx, box.ID, staff, dto.AddItemRequest{Name: "Lamp", Quantity: 1})
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = s.ImportItems(ctx, box.ID, staff, []dto.AddItemRequest{{Name: "Chair", Quantity: 2}})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		items, err := s.ListBoxItems(ctx, box.ID, staff)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Len(t, items, 2)
+
+		items, err = s.ListBoxItems(ctx, box.ID, owner)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Len(t, items, 2)
+	})
+
+	t.Run("read access does not allow adding", func(t *testing.T) {
+		_, err := s.ListBoxItems(ctx, box.ID, support)
+		assert.NoError(t, err)
+		_, err = s.AddItem(ctx, box.ID, support, dto.AddItemRequest{Name: "Lamp", Quantity: 1})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("other customers cannot see the box", func(t *testing.T) {
+		_, err := s.ListBoxItems(ctx, box.ID, stranger)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.AddItem(ctx, box.ID, stranger, dto.AddItemRequest{Name: "Lamp", Quantity: 1})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+// ledgerItemRepository holds one item in a box owned by ownerID and records
+// quantity movements; other methods are not used.
+type ledgerItemRepository struct {
+	repository.ItemRepository
+	item      models.Item
+	ownerID   uuid.UUID
+	movements []models.ItemQuantityMovement
+}
+
+func (r *ledgerItemRepository) GetByID(_ context.Context, itemID, userID uuid.UUID) (*models.Item, error) {
+	if itemID != r.item.ID || userID != r.ownerID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &r.item, nil
+}
+
+func (r *ledgerItemRepository) GetAnyByID(_ context.Context, itemID uuid.UUID) (*models.Item, error) {
+	if itemID != r.item.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &r.item, nil
+}
+
+func (r *ledgerItemRepository) AdjustQuantity(_ context.Context, movement *models.ItemQuantityMovement, _ *int) (*models.Item, error) {
+	r.item.Quantity += movement.Delta
+	movement.QuantityAfter = r.item.Quantity
+	r.movements = append(r.movements, *movement)
+	return &r.item, nil
+}
+
+func (r *ledgerItemRepository) ListQuantityMovements(_ context.Context, _ uuid.UUID) ([]models.ItemQuantityMovement, error) {
+	return r.movements, nil
+}
+
+func TestItemServiceQuantityAccess(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	itemRepo := &ledgerItemRepository{item: models.Item{ID: uuid.New(), Quantity: 4}, ownerID: customerID}
+	s := NewItemService(itemRepo, &fakeBoxRepository{}, nil, nil, nil, 0)
+
+	staff := policy.Principal{UserID: uuid.New(), Role: policy.WarehouseStaff}
+	support := policy.Principal{UserID: uuid.New(), Role: policy.Support}
+	stranger := policy.Principal{UserID: uuid.New(), Role: policy.Customer}
+	damaged := dto.QuantityAdjustmentRequest{Delta: -1, Reason: "damaged"}
+
+	t.Run("staff adjust a customer's item as themselves", func(t *testing.T) {
+		result, err := s.AdjustQuantity(ctx, itemRepo.item.ID, staff, damaged, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 3, result.Quantity)
+		assert.Equal(t, staff.UserID, result.Movement.ActorID)
+
+		movements, err := s.ListQuantityMovements(ctx, itemRepo.item.ID, staff)
+		assert.NoError(t, err)
+		assert.Len(t, movements, 1)
+	})
+
+	t.Run("read access does not allow adjusting", func(t *testing.T) {
+		_, err := s.ListQuantityMovements(ctx, itemRepo.item.ID, support)
+		assert.NoError(t, err)
+		_, err = s.AdjustQuantity(ctx, itemRepo.item.ID, support, damaged, nil)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("other customers cannot see the item", func(t *testing.T) {
+		_, err := s.ListQuantityMovements(ctx, itemRepo.item.ID, stranger)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = s.AdjustQuantity(ctx, itemRepo.item.ID, stranger, damaged, nil)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
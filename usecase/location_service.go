@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+)
+
+var ErrBoxNotPlaceable = errors.New("returned or disposed boxes cannot be placed at a location")
+
+type LocationService struct {
+	repo    repository.LocationRepository
+	boxRepo repository.BoxRepository
+}
+
+func NewLocationService(repo repository.LocationRepository, boxRepo repository.BoxRepository) *LocationService {
+	return &LocationService{
+		repo:    repo,
+		boxRepo: boxRepo,
+	}
+}
+
+func (s *LocationService) CreateLocation(ctx context.Context, req dto.CreateLocationRequest) (uuid.UUID, error) {
+	location := &models.StorageLocation{
+		ID:       uuid.New(),
+		Name:     req.Name,
+		Address:  req.Address,
+		Capacity: req.Capacity,
+	}
+	if err := s.repo.Create(ctx, location); err != nil {
+		return uuid.Nil, err
+	}
+	return location.ID, nil
+}
+
+func (s *LocationService) ListLocations(ctx context.Context) ([]dto.LocationResponse, error) {
+	locations, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.LocationResponse, 0, len(locations))
+	for _, location := range locations {
+		result = append(result, toLocationResponse(&location))
+	}
+	return result, nil
+}
+
+func (s *LocationService) GetLocation(ctx context.Context, id uuid.UUID) (*dto.LocationResponse, error) {
+	location, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := toLocationResponse(location)
+	return &resp, nil
+}
+
+func (s *LocationService) UpdateLocation(ctx context.Context, id uuid.UUID, req dto.UpdateLocationRequest) error {
+	var updates = map[string]interface{}{}
+
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Address != nil {
+		updates["address"] = *req.Address
+	}
+	if req.Capacity != nil {
+		updates["capacity"] = *req.Capacity
+	}
+
+	return s.repo.Update(ctx, id, updates)
+}
+
+func (s *LocationService) DeleteLocation(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// PlaceBox puts a box at a location, moving it out of its previous one if needed.
+func (s *LocationService) PlaceBox(ctx context.Context, boxID, locationID uuid.UUID) error {
+	box, err := s.boxRepo.FindAnyByID(ctx, boxID)
+	if err != nil {
+		return err
+	}
+	if box.Status == "returned" || box.Status == "disposed" {
+		return ErrBoxNotPlaceable
+	}
+	return s.repo.AssignBox(ctx, box.ID, &locationID)
+}
+
+// RemoveBox takes a box off its current location.
+func (s *LocationService) RemoveBox(ctx context.Context, boxID uuid.UUID) error {
+	return s.repo.AssignBox(ctx, boxID, nil)
+}
+
+func toLocationResponse(location *models.StorageLocation) dto.LocationResponse {
+	return dto.LocationResponse{
+		ID:          location.ID,
+		Name:        location.Name,
+		Address:     location.Address,
+		Capacity:    location.Capacity,
+		CurrentLoad: location.CurrentLoad,
+	}
+}
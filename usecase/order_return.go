@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+)
+
+var (
+	ErrOrderItemsNotReturn  = errors.New("only return orders can list items")
+	ErrWholeBoxReturnOnly   = errors.New("self-packed boxes can only be returned whole")
+	ErrOrderItemDuplicate   = errors.New("each item can only be listed once")
+	ErrOrderItemNotInBox    = errors.New("item is not in the box")
+	ErrOrderItemQuantity    = errors.New("quantity exceeds what is in the box")
+	ErrPickListNotAvailable = errors.New("pick lists are only available for return orders")
+)
+
+// partialReturnItems checks the items requested from box and turns them into
+// order lines.
+func partialReturnItems(box *models.Box, req dto.CreateOrderRequest) ([]models.StorageOrderItem, error) {
+	if req.Type != "return" {
+		return nil, ErrOrderItemsNotReturn
+	}
+	if box.PackingMode != "sort" {
+		return nil, ErrWholeBoxReturnOnly
+	}
+
+	inBox := make(map[uuid.UUID]int, len(box.Items))
+	for _, item := range box.Items {
+		inBox[item.ID] = item.Quantity
+	}
+	lines := make([]models.StorageOrderItem, 0, len(req.Items))
+	seen := make(map[uuid.UUID]bool, len(req.Items))
+	for _, requested := range req.Items {
+		if seen[requested.ItemID] {
+			return nil, fmt.Errorf("%w: %s", ErrOrderItemDuplicate, requested.ItemID)
+		}
+		seen[requested.ItemID] = true
+
+		quantity, ok := inBox[requested.ItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrOrderItemNotInBox, requested.ItemID)
+		}
+		if requested.Quantity > quantity {
+			return nil, fmt.Errorf("%w: %s has %d", ErrOrderItemQuantity, requested.ItemID, quantity)
+		}
+		lines = append(lines, models.StorageOrderItem{
+			ID:       uuid.New(),
+			ItemID:   requested.ItemID,
+			Quantity: requested.Quantity,
+		})
+	}
+	return lines, nil
+}
+
+// PickList tells employees where to find a return order's box and what to take
+// out of it: the requested items for partial returns, or everything in the box.
+func (s *OrderService) PickList(ctx context.Context, orderID uuid.UUID, caller policy.Principal) (*dto.PickListResponse, error) {
+	order, err := s.findAccessibleOrder(ctx, orderID, caller)
+	if err != nil {
+		return nil, err
+	}
+	if order.Type != "return" {
+		return nil, ErrPickListNotAvailable
+	}
+	box, err := s.boxRepo.FindAnyByID(ctx, order.BoxID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PickListResponse{
+		OrderID:       order.ID,
+		Status:        order.Status,
+		ScheduledDate: order.ScheduledDate,
+		BoxID:         box.ID,
+		BoxCode:       box.Code,
+		WholeBox:      len(order.Items) == 0,
+		Items:         []dto.PickListItem{},
+	}
+	if box.Location != nil {
+		resp.Location = &box.Location.Name
+	}
+
+	items := make(map[uuid.UUID]models.Item, len(box.Items))
+	for _, item := range box.Items {
+		items[item.ID] = item
+	}
+	if resp.WholeBox {
+		for _, item := range box.Items {
+			resp.Items = append(resp.Items, toPickListItem(item, item.Quantity))
+		}
+		return resp, nil
+	}
+	for _, line := range order.Items {
+		// An item moved out of the box since the order was placed shows as 0 in the box
+		item, ok := items[line.ItemID]
+		if !ok {
+			item = models.Item{ID: line.ItemID}
+		}
+		resp.Items = append(resp.Items, toPickListItem(item, line.Quantity))
+	}
+	return resp, nil
+}
+
+func toPickListItem(item models.Item, quantity int) dto.PickListItem {
+	return dto.PickListItem{
+		ItemID:      item.ID,
+		Name:        item.Name,
+		Description: item.Description,
+		Quantity:    quantity,
+		InBox:       item.Quantity,
+	}
+}
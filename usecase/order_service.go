@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrOrderAlreadyOpen       = errors.New("box already has an open order")
+	ErrOrderInvalidTransition = errors.New("order status transition not allowed")
+	ErrOrderBoxState          = errors.New("box status does not allow this order type")
+	ErrOrderDateInPast        = errors.New("scheduled_date must be in the future")
+)
+
+// orderTransitions lists the statuses an order may move to from its current status.
+var orderTransitions = map[string][]string{
+	"requested":   {"in_progress", "cancelled"},
+	"in_progress": {"completed", "cancelled"},
+}
+
+// orderBoxStatus is the box status required to schedule each order type.
+var orderBoxStatus = map[string]string{
+	"pickup":   "in_transit",
+	"return":   "stored",
+	"relocate": "stored",
+}
+
+type OrderService struct {
+	orderRepo repository.OrderRepository
+	boxRepo   repository.BoxRepository
+}
+
+func NewOrderService(orderRepo repository.OrderRepository, boxRepo repository.BoxRepository) *OrderService {
+	return &OrderService{
+		orderRepo: orderRepo,
+		boxRepo:   boxRepo,
+	}
+}
+
+// CreateOrder schedules an order for one of the caller's boxes, or for any box
+// with policy.OrderCreateAny.
+func (s *OrderService) CreateOrder(ctx context.Context, caller policy.Principal, req dto.CreateOrderRequest) (uuid.UUID, error) {
+	if req.Type == "relocate" {
+		if err := caller.Authorize(policy.OrderRelocate); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if !req.ScheduledDate.After(time.Now()) {
+		return uuid.Nil, ErrOrderDateInPast
+	}
+
+	var box *models.Box
+	var err error
+	if caller.Can(policy.OrderCreateAny) {
+		box, err = s.boxRepo.FindAnyByID(ctx, req.BoxID)
+	} else {
+		box, err = s.boxRepo.FindByID(ctx, req.BoxID, caller.UserID)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if box.Status != orderBoxStatus[req.Type] {
+		return uuid.Nil, ErrOrderBoxState
+	}
+	var lines []models.StorageOrderItem
+	if len(req.Items) > 0 {
+		lines, err = partialReturnItems(box, req)
+		if err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	_, err = s.orderRepo.FindOpenByBoxID(ctx, box.ID)
+	if err == nil {
+		return uuid.Nil, ErrOrderAlreadyOpen
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, err
+	}
+
+	order := &models.StorageOrder{
+		ID:            uuid.New(),
+		UserID:        box.UserID,
+		BoxID:         box.ID,
+		Type:          req.Type,
+		ScheduledDate: req.ScheduledDate.UTC(),
+		Status:        "requested",
+		Items:         lines,
+	}
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		if errors.Is(err, repository.ErrOpenOrderExists) {
+			return uuid.Nil, ErrOrderAlreadyOpen
+		}
+		return uuid.Nil, err
+	}
+	return order.ID, nil
+}
+
+// ListOrders returns the caller's orders, or every order with
+// policy.OrderReadAny. The status filter only applies to the latter.
+func (s *OrderService) ListOrders(ctx context.Context, caller policy.Principal, status string) ([]dto.OrderResponse, error) {
+	var orders []models.StorageOrder
+	var err error
+	if caller.Can(policy.OrderReadAny) {
+		orders, err = s.orderRepo.FindByStatus(ctx, status)
+	} else {
+		orders, err = s.orderRepo.FindByUserID(ctx, caller.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		result = append(result, toOrderResponse(&order))
+	}
+	return result, nil
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, orderID uuid.UUID, caller policy.Principal) (*dto.OrderResponse, error) {
+	order, err := s.findAccessibleOrder(ctx, orderID, caller)
+	if err != nil {
+		return nil, err
+	}
+	resp := toOrderResponse(order)
+	return &resp, nil
+}
+
+// UpdateStatus moves an order through its lifecycle, which needs
+// policy.OrderAssign. Without it callers may only cancel their own orders while
+// they are still requested. Completing a partial return takes the requested
+// quantities out of the box, which stays stored.
+func (s *OrderService) UpdateStatus(ctx context.Context, orderID uuid.UUID, caller policy.Principal, newStatus string) error {
+	order, err := s.findAccessibleOrder(ctx, orderID, caller)
+	if err != nil {
+		return err
+	}
+
+	ownCancel := order.UserID == caller.UserID && order.Status == "requested" && newStatus == "cancelled"
+	if !ownCancel {
+		if err := caller.Authorize(policy.OrderAssign); err != nil {
+			return err
+		}
+	}
+	if !contains(orderTransitions[order.Status], newStatus) {
+		return ErrOrderInvalidTransition
+	}
+
+	if newStatus == "completed" && len(order.Items) > 0 {
+		return s.orderRepo.CompletePartialReturn(ctx, order, caller.UserID)
+	}
+	// The transition was checked against order.Status, so it only applies if no
+	// one changed the order since
+	return s.orderRepo.UpdateStatus(ctx, order.ID, order.Status, newStatus)
+}
+
+func (s *OrderService) findAccessibleOrder(ctx context.Context, orderID uuid.UUID, caller policy.Principal) (*models.StorageOrder, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !caller.CanAccess(order.UserID, policy.OrderReadAny) {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func toOrderResponse(order *models.StorageOrder) dto.OrderResponse {
+	var items []dto.OrderItemResponse
+	for _, line := range order.Items {
+		items = append(items, dto.OrderItemResponse{ItemID: line.ItemID, Quantity: line.Quantity})
+	}
+	return dto.OrderResponse{
+		ID:            order.ID,
+		UserID:        order.UserID,
+		BoxID:         order.BoxID,
+		Type:          order.Type,
+		ScheduledDate: order.ScheduledDate,
+		Status:        order.Status,
+		Items:         items,
+		CreatedAt:     order.CreatedAt,
+	}
+}
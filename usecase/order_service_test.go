@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// racingOrderRepository finds no open order, like a request that checked just
+// before a concurrent one inserted, and then lets the unique index reject Create.
+type racingOrderRepository struct {
+	repository.OrderRepository
+}
+
+func (r *racingOrderRepository) FindOpenByBoxID(_ context.Context, _ uuid.UUID) (*models.StorageOrder, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *racingOrderRepository) Create(_ context.Context, _ *models.StorageOrder) error {
+	return repository.ErrOpenOrderExists
+}
+
+func TestCreateOrderLosingRaceReportsOpenOrder(t *testing.T) {
+	owner := uuid.New()
+	box := &models.Box{ID: uuid.New(), UserID: owner, Status: "stored"}
+	service := NewOrderService(&racingOrderRepository{}, &fakeBoxRepository{boxes: map[uuid.UUID]*models.Box{box.ID: box}})
+
+	_, err := service.CreateOrder(context.Background(), policy.Principal{UserID: owner, Role: policy.Customer}, dto.CreateOrderRequest{
+		BoxID:         box.ID,
+		Type:          "return",
+		ScheduledDate: time.Now().Add(24 * time.Hour),
+	})
+	assert.ErrorIs(t, err, ErrOrderAlreadyOpen)
+}
+
+// changingOrderRepository hands out the order and then lets a concurrent
+// request move it to concurrentStatus before UpdateStatus runs.
+type changingOrderRepository struct {
+	repository.OrderRepository
+	order            models.StorageOrder
+	concurrentStatus string
+}
+
+func (r *changingOrderRepository) FindByID(_ context.Context, _ uuid.UUID) (*models.StorageOrder, error) {
+	order := r.order
+	r.order.Status = r.concurrentStatus
+	return &order, nil
+}
+
+func (r *changingOrderRepository) UpdateStatus(_ context.Context, _ uuid.UUID, from, to string) error {
+	if r.order.Status != from {
+		return repository.ErrVersionConflict
+	}
+	r.order.Status = to
+	return nil
+}
+
+func TestUpdateOrderStatusLosingRaceReportsConflict(t *testing.T) {
+	owner := uuid.New()
+	repo := &changingOrderRepository{
+		order:            models.StorageOrder{ID: uuid.New(), UserID: owner, Status: "requested"},
+		concurrentStatus: "completed",
+	}
+	service := NewOrderService(repo, nil)
+
+	err := service.UpdateStatus(context.Background(), repo.order.ID, policy.Principal{UserID: owner, Role: policy.Customer}, "cancelled")
+
+	assert.ErrorIs(t, err, repository.ErrVersionConflict)
+	assert.Equal(t, "completed", repo.order.Status)
+}
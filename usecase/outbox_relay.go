@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sandroJayas/storage-service/domain/event"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	outboxBatchSize   = 100
+	outboxLease       = time.Minute
+	outboxBaseBackoff = time.Second
+	outboxMaxBackoff  = 5 * time.Minute
+)
+
+// OutboxRelay polls the outbox and publishes pending events. An event is only
+// marked published after the publisher accepts it; failures are retried with
+// exponential backoff, so delivery is at-least-once.
+type OutboxRelay struct {
+	repo      repository.OutboxRepository
+	publisher event.Publisher
+	interval  time.Duration
+}
+
+func NewOutboxRelay(repo repository.OutboxRepository, publisher event.Publisher, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		publisher: publisher,
+		interval:  interval,
+	}
+}
+
+// Run relays events until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			n, err := r.RelayOnce(ctx)
+			if err != nil {
+				utils.Logger.Error("outbox relay batch failed", zap.Error(err))
+			}
+			// Keep draining while batches come back full.
+			if err != nil || n < outboxBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce claims one batch of due events and tries to publish each of them.
+// It returns the number of events claimed.
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	events, err := r.repo.ClaimPending(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range events {
+		err := r.publisher.Publish(ctx, event.Event{
+			ID:            e.ID,
+			Type:          e.EventType,
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			OccurredAt:    e.CreatedAt,
+			Payload:       json.RawMessage(e.Payload),
+		})
+		if err != nil {
+			utils.Logger.Warn("outbox event publish failed",
+				zap.String("event_id", e.ID.String()),
+				zap.String("event_type", e.EventType),
+				zap.Int("attempts", e.Attempts+1),
+				zap.Error(err))
+			if markErr := r.repo.MarkFailed(ctx, e.ID, time.Now().Add(outboxBackoff(e.Attempts+1)), err.Error()); markErr != nil {
+				return len(events), markErr
+			}
+			continue
+		}
+
+		if err := r.repo.MarkPublished(ctx, e.ID); err != nil {
+			return len(events), err
+		}
+	}
+	return len(events), nil
+}
+
+// outboxBackoff doubles the delay with every failed attempt, up to outboxMaxBackoff.
+func outboxBackoff(attempts int) time.Duration {
+	delay := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return delay
+}
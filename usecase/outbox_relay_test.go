@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/infrastructure/publisher"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeOutboxRepository mimics the claim/lease semantics of GormOutboxRepository in memory.
+type fakeOutboxRepository struct {
+	mu     sync.Mutex
+	events []*models.OutboxEvent
+}
+
+func (r *fakeOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []models.OutboxEvent
+	now := time.Now()
+	for _, e := range r.events {
+		if e.PublishedAt == nil && !e.NextAttemptAt.After(now) && len(claimed) < limit {
+			claimed = append(claimed, *e)
+			e.NextAttemptAt = now.Add(lease)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *fakeOutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.find(id).PublishedAt = &now
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.find(id)
+	e.Attempts++
+	e.NextAttemptAt = nextAttemptAt
+	e.LastError = lastError
+	return nil
+}
+
+func (r *fakeOutboxRepository) find(id uuid.UUID) *models.OutboxEvent {
+	for _, e := range r.events {
+		if e.ID == id {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestOutboxRelay(t *testing.T) {
+	utils.InitLogger()
+
+	newEvent := func() *models.OutboxEvent {
+		return &models.OutboxEvent{
+			ID:            uuid.New(),
+			AggregateType: "box",
+			AggregateID:   uuid.New(),
+			EventType:     "box.created",
+			Payload:       `{"status":"in_transit"}`,
+			NextAttemptAt: time.Now().Add(-time.Second),
+		}
+	}
+
+	t.Run("publishes pending events once", func(t *testing.T) {
+		repo := &fakeOutboxRepository{events: []*models.OutboxEvent{newEvent(), newEvent()}}
+		pub := publisher.NewMemoryPublisher()
+		relay := NewOutboxRelay(repo, pub, time.Second)
+
+		n, err := relay.RelayOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, 2, len(pub.Events()))
+		assert.Equal(t, "box.created", pub.Events()[0].Type)
+		assert.JSONEq(t, `{"status":"in_transit"}`, string(pub.Events()[0].Payload))
+
+		n, err = relay.RelayOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, 2, len(pub.Events()))
+	})
+
+	t.Run("failed publish is retried after backoff", func(t *testing.T) {
+		e := newEvent()
+		repo := &fakeOutboxRepository{events: []*models.OutboxEvent{e}}
+		pub := publisher.NewMemoryPublisher()
+		pub.FailNext = 1
+		pub.Err = errors.New("consumer unavailable")
+		relay := NewOutboxRelay(repo, pub, time.Second)
+
+		_, err := relay.RelayOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, pub.Events())
+		assert.Nil(t, e.PublishedAt)
+		assert.Equal(t, 1, e.Attempts)
+		assert.Equal(t, "consumer unavailable", e.LastError)
+		assert.True(t, e.NextAttemptAt.After(time.Now()))
+
+		// Not due yet, so nothing is claimed.
+		n, _ := relay.RelayOnce(context.Background())
+		assert.Equal(t, 0, n)
+
+		e.NextAttemptAt = time.Now().Add(-time.Second)
+		_, err = relay.RelayOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(pub.Events()))
+		assert.NotNil(t, e.PublishedAt)
+	})
+
+	t.Run("backoff grows exponentially and is capped", func(t *testing.T) {
+		assert.Equal(t, time.Second, outboxBackoff(1))
+		assert.Equal(t, 2*time.Second, outboxBackoff(2))
+		assert.Equal(t, 8*time.Second, outboxBackoff(4))
+		assert.Equal(t, outboxMaxBackoff, outboxBackoff(30))
+	})
+}
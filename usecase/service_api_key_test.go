@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/sandroJayas/storage-service/models"
+	"github.com/sandroJayas/storage-service/policy"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type fakeServiceAPIKeyRepository struct {
+	keys []*models.ServiceAPIKey
+}
+
+func (r *fakeServiceAPIKeyRepository) Create(ctx context.Context, key *models.ServiceAPIKey) error {
+	key.CreatedAt = time.Now()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *fakeServiceAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*models.ServiceAPIKey, error) {
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeServiceAPIKeyRepository) FindAll(ctx context.Context) ([]models.ServiceAPIKey, error) {
+	var keys []models.ServiceAPIKey
+	for _, key := range r.keys {
+		keys = append(keys, *key)
+	}
+	return keys, nil
+}
+
+func (r *fakeServiceAPIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) (*models.ServiceAPIKey, error) {
+	for _, key := range r.keys {
+		if key.ID == id {
+			if key.RevokedAt == nil {
+				now := time.Now()
+				key.RevokedAt = &now
+			}
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestServiceAPIKeyService(t *testing.T) {
+	ctx := context.Background()
+	admin := policy.Principal{UserID: uuid.New(), Role: policy.Admin}
+
+	t.Run("issued key authenticates until revoked", func(t *testing.T) {
+		repo := &fakeServiceAPIKeyRepository{}
+		s := NewServiceAPIKeyService(repo)
+
+		created, err := s.CreateKey(ctx, admin, dto.CreateServiceAPIKeyRequest{Name: "billing", Scopes: []string{"order:read_any"}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, strings.HasPrefix(created.Key, apiKeyPrefix))
+		assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+
+		key, err := s.Authenticate(ctx, created.Key)
+		if !assert.NoError(t, err) || !assert.NotNil(t, key) {
+			return
+		}
+		assert.Equal(t, created.ID, key.ID)
+		assert.Equal(t, []string{"order:read_any"}, key.Scopes)
+
+		key, err = s.Authenticate(ctx, created.Key+"x")
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+
+		_, err = s.RevokeKey(ctx, created.ID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		key, err = s.Authenticate(ctx, created.Key)
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+	})
+
+	t.Run("expired key is rejected", func(t *testing.T) {
+		s := NewServiceAPIKeyService(&fakeServiceAPIKeyRepository{})
+		expiresAt := time.Now().Add(time.Hour)
+		created, err := s.CreateKey(ctx, admin, dto.CreateServiceAPIKeyRequest{Name: "reports", Scopes: []string{"box:read_any"}, ExpiresAt: &expiresAt})
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		s.now = func() time.Time { return expiresAt.Add(time.Second) }
+		key, err := s.Authenticate(ctx, created.Key)
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+	})
+
+	t.Run("invalid requests are rejected", func(t *testing.T) {
+		s := NewServiceAPIKeyService(&fakeServiceAPIKeyRepository{})
+		_, err := s.CreateKey(ctx, admin, dto.CreateServiceAPIKeyRequest{Name: "x", Scopes: []string{"order:read_any", "root"}})
+		assert.ErrorIs(t, err, ErrUnknownScope)
+
+		past := time.Now().Add(-time.Minute)
+		_, err = s.CreateKey(ctx, admin, dto.CreateServiceAPIKeyRequest{Name: "x", Scopes: []string{"token:revoke"}, ExpiresAt: &past})
+		assert.ErrorIs(t, err, ErrAPIKeyExpiresAt)
+	})
+
+	t.Run("scopes are capped at the issuer's permissions", func(t *testing.T) {
+		s := NewServiceAPIKeyService(&fakeServiceAPIKeyRepository{})
+		staff := policy.Principal{UserID: uuid.New(), Role: policy.WarehouseStaff}
+		_, err := s.CreateKey(ctx, staff, dto.CreateServiceAPIKeyRequest{Name: "x", Scopes: []string{"box:read_any", "token:revoke"}})
+		assert.ErrorIs(t, err, policy.ErrForbidden)
+	})
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/domain/repository"
+	"github.com/sandroJayas/storage-service/dto"
+)
+
+// maxRevocationCacheEntries bounds each of the revocation caches. Expired
+// entries are swept when it is reached, and the cache is cleared if that is
+// not enough.
+const maxRevocationCacheEntries = 10000
+
+// TokenRevocationService records revoked access tokens and answers whether a
+// token is revoked. Answers are cached for cacheTTL, so a revocation made
+// through another instance takes up to cacheTTL to apply here.
+type TokenRevocationService struct {
+	repo     repository.RevocationRepository
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	tokens  map[string]cachedTokenRevocation
+	cutoffs map[uuid.UUID]cachedUserCutoff
+}
+
+type cachedTokenRevocation struct {
+	revoked bool
+	until   time.Time
+}
+
+type cachedUserCutoff struct {
+	cutoff time.Time
+	until  time.Time
+}
+
+func NewTokenRevocationService(repo repository.RevocationRepository, cacheTTL time.Duration) *TokenRevocationService {
+	return &TokenRevocationService{
+		repo:     repo,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+		tokens:   make(map[string]cachedTokenRevocation),
+		cutoffs:  make(map[uuid.UUID]cachedUserCutoff),
+	}
+}
+
+func (s *TokenRevocationService) RevokeToken(ctx context.Context, req dto.RevokeTokenRequest) error {
+	if err := s.repo.RevokeToken(ctx, req.JTI, req.ExpiresAt); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheToken(req.JTI, true)
+	return nil
+}
+
+// RevokeUserTokens revokes the user's tokens issued before req.IssuedBefore, or
+// before now if it is not set, and returns the cutoff it applied.
+func (s *TokenRevocationService) RevokeUserTokens(ctx context.Context, req dto.RevokeUserTokensRequest) (time.Time, error) {
+	before := s.now()
+	if req.IssuedBefore != nil {
+		before = *req.IssuedBefore
+	}
+	if err := s.repo.RevokeUserTokens(ctx, req.UserID, before); err != nil {
+		return time.Time{}, err
+	}
+
+	// The stored cutoff may be later than this one, so it is reloaded on next use
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cutoffs, req.UserID)
+	return before, nil
+}
+
+// IsRevoked reports whether the user's token with the given jti and issue time
+// has been revoked. A token without an issue time counts as issued before any
+// cutoff, and one without a jti can only be revoked by a cutoff.
+func (s *TokenRevocationService) IsRevoked(ctx context.Context, userID uuid.UUID, jti string, issuedAt time.Time) (bool, error) {
+	cutoff, err := s.userCutoff(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !cutoff.IsZero() && (issuedAt.IsZero() || issuedAt.Before(cutoff)) {
+		return true, nil
+	}
+	if jti == "" {
+		return false, nil
+	}
+	return s.tokenRevoked(ctx, jti)
+}
+
+func (s *TokenRevocationService) userCutoff(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	s.mu.Lock()
+	cached, ok := s.cutoffs[userID]
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.until) {
+		return cached.cutoff, nil
+	}
+
+	cutoff, err := s.repo.UserTokenCutoff(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cacheTTL > 0 {
+		if len(s.cutoffs) >= maxRevocationCacheEntries {
+			s.cutoffs = sweepExpired(s.cutoffs, s.now(), func(c cachedUserCutoff) time.Time { return c.until })
+		}
+		s.cutoffs[userID] = cachedUserCutoff{cutoff: cutoff, until: s.now().Add(s.cacheTTL)}
+	}
+	return cutoff, nil
+}
+
+func (s *TokenRevocationService) tokenRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.Lock()
+	cached, ok := s.tokens[jti]
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.until) {
+		return cached.revoked, nil
+	}
+
+	revoked, err := s.repo.IsTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheToken(jti, revoked)
+	return revoked, nil
+}
+
+// cacheToken must be called with s.mu held.
+func (s *TokenRevocationService) cacheToken(jti string, revoked bool) {
+	if s.cacheTTL <= 0 {
+		return
+	}
+	if len(s.tokens) >= maxRevocationCacheEntries {
+		s.tokens = sweepExpired(s.tokens, s.now(), func(c cachedTokenRevocation) time.Time { return c.until })
+	}
+	s.tokens[jti] = cachedTokenRevocation{revoked: revoked, until: s.now().Add(s.cacheTTL)}
+}
+
+// sweepExpired drops the entries whose until has passed, or all of them if that
+// still leaves the cache full.
+func sweepExpired[K comparable, V any](cache map[K]V, now time.Time, until func(V) time.Time) map[K]V {
+	for key, entry := range cache {
+		if !now.Before(until(entry)) {
+			delete(cache, key)
+		}
+	}
+	if len(cache) >= maxRevocationCacheEntries {
+		return make(map[K]V)
+	}
+	return cache
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandroJayas/storage-service/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRevocationRepository keeps revocations in memory and counts lookups.
+type fakeRevocationRepository struct {
+	mu      sync.Mutex
+	tokens  map[string]time.Time
+	cutoffs map[uuid.UUID]time.Time
+	lookups int
+}
+
+func newFakeRevocationRepository() *fakeRevocationRepository {
+	return &fakeRevocationRepository{tokens: map[string]time.Time{}, cutoffs: map[uuid.UUID]time.Time{}}
+}
+
+func (r *fakeRevocationRepository) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[jti] = expiresAt
+	return nil
+}
+
+func (r *fakeRevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	_, ok := r.tokens[jti]
+	return ok, nil
+}
+
+func (r *fakeRevocationRepository) RevokeUserTokens(ctx context.Context, userID uuid.UUID, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if before.After(r.cutoffs[userID]) {
+		r.cutoffs[userID] = before
+	}
+	return nil
+}
+
+func (r *fakeRevocationRepository) UserTokenCutoff(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	return r.cutoffs[userID], nil
+}
+
+func (r *fakeRevocationRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestTokenRevocationService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 500_000_000, time.UTC)
+	newService := func(repo *fakeRevocationRepository, ttl time.Duration) *TokenRevocationService {
+		s := NewTokenRevocationService(repo, ttl)
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	t.Run("revoked jti is rejected at once", func(t *testing.T) {
+		repo := newFakeRevocationRepository()
+		s := newService(repo, time.Minute)
+		userID := uuid.New()
+
+		revoked, err := s.IsRevoked(ctx, userID, "token-1", now.Add(-time.Hour))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.False(t, revoked)
+
+		// The cached "not revoked" answer is replaced by the revocation
+		if !assert.NoError(t, s.RevokeToken(ctx, dto.RevokeTokenRequest{JTI: "token-1", ExpiresAt: now.Add(time.Hour)})) {
+			return
+		}
+		revoked, err = s.IsRevoked(ctx, userID, "token-1", now.Add(-time.Hour))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, revoked)
+
+		revoked, err = s.IsRevoked(ctx, userID, "token-2", now.Add(-time.Hour))
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.False(t, revoked)
+	})
+
+	t.Run("user cutoff rejects tokens issued before it", func(t *testing.T) {
+		repo := newFakeRevocationRepository()
+		s := newService(repo, time.Minute)
+		userID := uuid.New()
+
+		_, err := s.IsRevoked(ctx, userID, "", now.Add(-time.Hour))
+		if !assert.NoError(t, err) {
+			return
+		}
+		cutoff, err := s.RevokeUserTokens(ctx, dto.RevokeUserTokensRequest{UserID: userID})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, now, cutoff)
+
+		cases := []struct {
+			name     string
+			issuedAt time.Time
+			revoked  bool
+		}{
+			{"issued an hour before", now.Add(-time.Hour), true},
+			// iat has whole seconds, so the second of the cutoff counts as before it
+			{"issued in the same second", now.Truncate(time.Second), true},
+			{"issued after", now.Add(time.Second).Truncate(time.Second), false},
+			{"no issue time", time.Time{}, true},
+		}
+		for _, tc := range cases {
+			revoked, err := s.IsRevoked(ctx, userID, "", tc.issuedAt)
+			if !assert.NoError(t, err, tc.name) {
+				return
+			}
+			assert.Equal(t, tc.revoked, revoked, tc.name)
+		}
+
+		revoked, err := s.IsRevoked(ctx, uuid.New(), "", time.Time{})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.False(t, revoked, "other users are unaffected")
+	})
+
+	t.Run("answers are cached for the TTL", func(t *testing.T) {
+		repo := newFakeRevocationRepository()
+		s := newService(repo, time.Minute)
+		userID := uuid.New()
+
+		for i := 0; i < 3; i++ {
+			_, err := s.IsRevoked(ctx, userID, "token-1", now)
+			if !assert.NoError(t, err) {
+				return
+			}
+		}
+		assert.Equal(t, 2, repo.lookups)
+
+		// A revocation written by another instance shows up once the cache expires
+		if !assert.NoError(t, repo.RevokeToken(ctx, "token-1", now.Add(time.Hour))) {
+			return
+		}
+		revoked, _ := s.IsRevoked(ctx, userID, "token-1", now)
+		assert.False(t, revoked)
+
+		now = now.Add(time.Minute)
+		revoked, err := s.IsRevoked(ctx, userID, "token-1", now)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, revoked)
+		assert.Equal(t, 4, repo.lookups)
+	})
+
+	t.Run("zero TTL disables the cache", func(t *testing.T) {
+		repo := newFakeRevocationRepository()
+		s := newService(repo, 0)
+
+		for i := 0; i < 3; i++ {
+			_, err := s.IsRevoked(ctx, uuid.New(), "token-1", now)
+			if !assert.NoError(t, err) {
+				return
+			}
+		}
+		assert.Equal(t, 6, repo.lookups)
+	})
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// BoxCodeLength is the number of characters in a box code.
+const BoxCodeLength = 8
+
+// boxCodeAlphabet leaves out 0, O, 1 and I, which are easily confused when
+// read aloud or off a label.
+const boxCodeAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// NewBoxCode returns a random box code such as "K7QX2MHD".
+func NewBoxCode() (string, error) {
+	buf := make([]byte, BoxCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	// The alphabet has 32 characters, so the low five bits pick one uniformly
+	for i, b := range buf {
+		buf[i] = boxCodeAlphabet[b&31]
+	}
+	return string(buf), nil
+}
+
+// ParseBoxCode normalises a code typed by a person: case and a separating
+// hyphen or space are ignored. It reports false if s cannot be a box code.
+func ParseBoxCode(s string) (string, bool) {
+	code := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+	if len(code) != BoxCodeLength {
+		return "", false
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(boxCodeAlphabet, r) {
+			return "", false
+		}
+	}
+	return code, true
+}
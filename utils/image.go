@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxImagePixels bounds the width times height of images GenerateThumbnail
+// decodes. A small file can declare huge dimensions, and decoding allocates
+// memory for all of them.
+const MaxImagePixels = 40_000_000
+
+var ErrImageTooManyPixels = fmt.Errorf("image exceeds %d megapixels", MaxImagePixels/1_000_000)
+
+// GenerateThumbnail decodes a JPEG, PNG or WebP image and returns a JPEG scaled
+// down to fit within maxDim x maxDim. Smaller images are re-encoded unscaled.
+// Images above MaxImagePixels are rejected with ErrImageTooManyPixels before
+// they are decoded.
+func GenerateThumbnail(data []byte, maxDim int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, errors.New("image has no pixels")
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, ErrImageTooManyPixels
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > maxDim || height > maxDim {
+		if width >= height {
+			height = height * maxDim / width
+			width = maxDim
+		} else {
+			width = width * maxDim / height
+			height = maxDim
+		}
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.White)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withDeclaredSize rewrites the IHDR chunk of a PNG so it declares width x height
+// while the pixel data stays tiny.
+func withDeclaredSize(data []byte, width, height uint32) []byte {
+	out := append([]byte(nil), data...)
+	// 8 byte signature, 4 byte length, 4 byte "IHDR", then width and height.
+	binary.BigEndian.PutUint32(out[16:20], width)
+	binary.BigEndian.PutUint32(out[20:24], height)
+	binary.BigEndian.PutUint32(out[29:33], crc32.ChecksumIEEE(out[12:29]))
+	return out
+}
+
+func TestGenerateThumbnailScalesDown(t *testing.T) {
+	thumbnail, err := GenerateThumbnail(encodePNG(t, 800, 400), 200)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 200, cfg.Width)
+	assert.Equal(t, 100, cfg.Height)
+}
+
+func TestGenerateThumbnailRejectsTooManyPixels(t *testing.T) {
+	bomb := withDeclaredSize(encodePNG(t, 1, 1), 50_000, 50_000)
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(bomb))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 50_000, cfg.Width)
+
+	_, err = GenerateThumbnail(bomb, 200)
+	assert.ErrorIs(t, err, ErrImageTooManyPixels)
+}
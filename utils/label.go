@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// LabelQRSize is the width of the QR code on PNG labels in pixels.
+const LabelQRSize = 320
+
+// QRBitmap encodes content as a QR code, including its quiet zone. True is a
+// dark module.
+func QRBitmap(content string) ([][]bool, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	return qr.Bitmap(), nil
+}
+
+// RenderLabelPNG draws a QR code for content with the caption lines centred
+// underneath it.
+func RenderLabelPNG(content string, caption ...string) ([]byte, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+
+	face := basicfont.Face7x13
+	lineHeight := face.Metrics().Height.Ceil() + 4
+	img := image.NewRGBA(image.Rect(0, 0, LabelQRSize, LabelQRSize+len(caption)*lineHeight+8))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, LabelQRSize, LabelQRSize), qr.Image(LabelQRSize), image.Point{}, draw.Src)
+
+	d := font.Drawer{Dst: img, Src: image.NewUniform(color.Black), Face: face}
+	for i, line := range caption {
+		x := (fixed.I(LabelQRSize) - d.MeasureString(line)) / 2
+		if x < 0 {
+			x = 0
+		}
+		d.Dot = fixed.Point26_6{X: x, Y: fixed.I(LabelQRSize + (i+1)*lineHeight)}
+		d.DrawString(line)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size and margins in PDF points.
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+// PDFWriter writes a text-only A4 document using the built-in Helvetica fonts.
+// Each page is written out as soon as it is full, so only the current page is
+// held in memory no matter how long the document gets.
+type PDFWriter struct {
+	w       *bufio.Writer
+	written int64
+	offsets map[int]int64
+	nextObj int
+	pages   []int
+	page    bytes.Buffer
+	y       float64
+	err     error
+}
+
+// Fixed object numbers; pages are numbered from pdfFirstPageObj on.
+const (
+	pdfCatalogObj   = 1
+	pdfPagesObj     = 2
+	pdfFontObj      = 3
+	pdfBoldFontObj  = 4
+	pdfFirstPageObj = 5
+)
+
+func NewPDFWriter(w io.Writer) *PDFWriter {
+	p := &PDFWriter{
+		w:       bufio.NewWriter(w),
+		offsets: map[int]int64{},
+		nextObj: pdfFirstPageObj,
+		y:       pdfPageHeight - pdfMargin,
+	}
+	p.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	p.writeObject(pdfFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	p.writeObject(pdfBoldFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	return p
+}
+
+// Line writes one line of text at the given indent below the previous line,
+// starting a new page when the current one is full. Text that does not fit the
+// page width is cut off with an ellipsis.
+func (p *PDFWriter) Line(text string, size float64, bold bool, indent float64) {
+	lineHeight := size * 1.4
+	if p.y-lineHeight < pdfMargin {
+		p.NewPage()
+	}
+	p.y -= lineHeight
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	// Helvetica averages about half an em per character
+	maxChars := int((pdfPageWidth - 2*pdfMargin - indent) / (size * 0.5))
+	if runes := []rune(text); len(runes) > maxChars {
+		text = string(runes[:maxChars-3]) + "..."
+	}
+	fmt.Fprintf(&p.page, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, size, pdfMargin+indent, p.y, pdfEscape(text))
+}
+
+// Bitmap draws a grid of black squares, such as a QR code, size points wide
+// at the given indent below the previous line.
+func (p *PDFWriter) Bitmap(bits [][]bool, size float64, indent float64) {
+	if len(bits) == 0 {
+		return
+	}
+	if p.y-size < pdfMargin {
+		p.NewPage()
+	}
+	module := size / float64(len(bits))
+	p.page.WriteString("0 g\n")
+	for row, line := range bits {
+		y := p.y - float64(row+1)*module
+		// One rectangle per run of dark modules keeps the stream small
+		for col := 0; col < len(line); col++ {
+			if !line[col] {
+				continue
+			}
+			start := col
+			for col+1 < len(line) && line[col+1] {
+				col++
+			}
+			fmt.Fprintf(&p.page, "%.2f %.2f %.2f %.2f re\n", pdfMargin+indent+float64(start)*module, y, float64(col-start+1)*module, module)
+		}
+	}
+	p.page.WriteString("f\n")
+	p.y -= size
+}
+
+// KeepTogether starts a new page unless height points still fit on this one, so
+// a block of that height is not split across pages.
+func (p *PDFWriter) KeepTogether(height float64) {
+	if p.y-height < pdfMargin {
+		p.NewPage()
+	}
+}
+
+// Space moves the cursor down without writing anything.
+func (p *PDFWriter) Space(height float64) {
+	p.y -= height
+}
+
+// NewPage finishes the current page and starts an empty one.
+func (p *PDFWriter) NewPage() {
+	p.flushPage()
+	p.y = pdfPageHeight - pdfMargin
+}
+
+// Close writes the last page and the document trailer. It does not close the
+// underlying writer.
+func (p *PDFWriter) Close() error {
+	p.flushPage()
+
+	kids := make([]string, len(p.pages))
+	for i, obj := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", obj)
+	}
+	p.writeObject(pdfPagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	p.writeObject(pdfCatalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObj))
+
+	xref := p.written
+	p.printf("xref\n0 %d\n0000000000 65535 f \n", p.nextObj)
+	for obj := 1; obj < p.nextObj; obj++ {
+		p.printf("%010d 00000 n \n", p.offsets[obj])
+	}
+	p.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.nextObj, pdfCatalogObj, xref)
+
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+// flushPage writes the current page, unless it is empty and not the only one.
+func (p *PDFWriter) flushPage() {
+	if p.page.Len() == 0 && len(p.pages) > 0 {
+		return
+	}
+
+	var content bytes.Buffer
+	zw := zlib.NewWriter(&content)
+	zw.Write(p.page.Bytes())
+	zw.Close()
+	p.page.Reset()
+
+	contentObj := p.nextObj
+	pageObj := p.nextObj + 1
+	p.nextObj += 2
+
+	p.offsets[contentObj] = p.written
+	p.printf("%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", contentObj, content.Len())
+	p.write(content.Bytes())
+	p.printf("\nendstream\nendobj\n")
+
+	p.writeObject(pageObj, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Contents %d 0 R /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> >>",
+		pdfPagesObj, pdfPageWidth, pdfPageHeight, contentObj, pdfFontObj, pdfBoldFontObj))
+	p.pages = append(p.pages, pageObj)
+
+	// Hand finished pages to the client rather than buffering them
+	if p.err == nil {
+		p.err = p.w.Flush()
+	}
+}
+
+func (p *PDFWriter) writeObject(obj int, body string) {
+	p.offsets[obj] = p.written
+	p.printf("%d 0 obj\n%s\nendobj\n", obj, body)
+}
+
+func (p *PDFWriter) printf(format string, args ...interface{}) {
+	p.write([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (p *PDFWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.err = err
+}
+
+// pdfEscape encodes text for a WinAnsi string literal. Latin-1 characters are
+// kept, anything else becomes a question mark.
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}